@@ -2,21 +2,20 @@
 package main
 
 import (
+	"context"
 	"flag"
 	"fmt"
 	"math/rand"
-	"os"
 	"os/signal"
 	"runtime/debug"
-	"strings"
 	"syscall"
 	"time"
 
-	"github.com/jinzhu/configor"
 	log "github.com/sirupsen/logrus"
 
 	"code.vegaprotocol.io/priceproxy/config"
 	"code.vegaprotocol.io/priceproxy/service"
+	"code.vegaprotocol.io/priceproxy/tracing"
 )
 
 var (
@@ -32,8 +31,10 @@ func main() {
 
 	var configName string
 	var configVersion bool
+	var watchConfigFile bool
 	flag.StringVar(&configName, "config", "", "Configuration YAML file")
 	flag.BoolVar(&configVersion, "version", false, "Show version")
+	flag.BoolVar(&watchConfigFile, "watch-config", false, "Reload the configuration when the file changes (it is always reloaded on SIGHUP)")
 	flag.Parse()
 
 	if configVersion {
@@ -41,19 +42,11 @@ func main() {
 		return
 	}
 
-	var cfg config.Config
-	err := configor.Load(&cfg, configName)
-	// https://github.com/jinzhu/configor/issues/40
-	if err != nil && !strings.Contains(err.Error(), "should be struct") {
-		log.WithFields(log.Fields{
-			"error": err.Error(),
-		}).Fatal("Failed to read config")
-	}
-	err = config.CheckConfig(&cfg)
+	cfg, err := config.LoadConfig(configName)
 	if err != nil {
 		log.WithFields(log.Fields{
 			"error": err.Error(),
-		}).Fatal("Config checks failed")
+		}).Fatal("Failed to load config")
 	}
 
 	err = config.ConfigureLogging(cfg.Server)
@@ -68,8 +61,18 @@ func main() {
 		"hash":    VersionHash,
 	}).Info("Version")
 
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	stopTracing, err := tracing.Start(ctx, cfg.Tracing, Version)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"error": err.Error(),
+		}).Fatal("Failed to start tracing")
+	}
+
 	var s *service.Service
-	s, err = service.NewService(cfg)
+	s, err = service.NewService(ctx, cfg, service.WithConfigFile(configName))
 	if err != nil {
 		log.WithFields(log.Fields{
 			"error": err.Error(),
@@ -85,11 +88,27 @@ func main() {
 			}).Fatal("Could not listen")
 		}
 	}()
-	c := make(chan os.Signal, 2)
-	signal.Notify(c, syscall.SIGINT)
-	signal.Notify(c, syscall.SIGTERM)
-	<-c
+	go func() {
+		err := s.StartGRPC()
+		if err != nil {
+			log.WithFields(log.Fields{
+				"listen": cfg.Server.GRPCListen,
+				"extra":  err.Error(),
+			}).Fatal("Could not listen for gRPC")
+		}
+	}()
+	go watchConfig(ctx, s, configName, watchConfigFile)
+
+	<-ctx.Done()
 	s.Stop()
+
+	flushCtx, cancelFlush := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancelFlush()
+	if err := stopTracing(flushCtx); err != nil {
+		log.WithFields(log.Fields{
+			"error": err.Error(),
+		}).Warn("Failed to flush traces")
+	}
 }
 
 func setCommitHash() {
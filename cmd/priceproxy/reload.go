@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	log "github.com/sirupsen/logrus"
+
+	"code.vegaprotocol.io/priceproxy/config"
+	"code.vegaprotocol.io/priceproxy/service"
+)
+
+// reloadDebounce groups the several file events editors make when saving a file into one reload.
+const reloadDebounce = 500 * time.Millisecond
+
+// configReloader applies the config file to the service again when told to.
+type configReloader struct {
+	configName string
+	apply      func(cfg config.Config) error
+	debounce   time.Duration
+}
+
+// reload reads the config file again and applies it. A config which cannot be read or fails the checks is
+// logged and ignored, the service keeps running with the previous one.
+func (r *configReloader) reload() {
+	cfg, err := config.LoadConfig(r.configName)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"error": err.Error(),
+			"file":  r.configName,
+		}).Error("Failed to reload config, keeping the current one")
+		return
+	}
+
+	if err := r.apply(cfg); err != nil {
+		log.WithFields(log.Fields{
+			"error": err.Error(),
+			"file":  r.configName,
+		}).Error("Failed to apply reloaded config, keeping the current one")
+		return
+	}
+
+	log.WithFields(log.Fields{
+		"file": r.configName,
+	}).Info("Reloaded config")
+}
+
+// run reloads the config on every signal received from hup, and once file events about the config file
+// stop for the debounce period. Either channel may be nil. It returns when ctx is done.
+func (r *configReloader) run(ctx context.Context, hup <-chan os.Signal, fileEvents <-chan fsnotify.Event, fileErrors <-chan error) {
+	debounce := time.NewTimer(r.debounce)
+	debounce.Stop()
+	defer debounce.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-hup:
+			log.Info("Received SIGHUP, reloading config")
+			r.reload()
+		case event := <-fileEvents:
+			if filepath.Clean(event.Name) == filepath.Clean(r.configName) &&
+				event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename) != 0 {
+				debounce.Reset(r.debounce)
+			}
+		case err := <-fileErrors:
+			log.WithFields(log.Fields{
+				"error": err.Error(),
+			}).Warn("Config file watcher failed")
+		case <-debounce.C:
+			log.WithFields(log.Fields{
+				"file": r.configName,
+			}).Info("Config file changed, reloading config")
+			r.reload()
+		}
+	}
+}
+
+// newConfigWatcher watches the directory of the config file, as editors and config management often replace
+// the file rather than write it.
+func newConfigWatcher(configName string) (*fsnotify.Watcher, error) {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return nil, err
+	}
+	if err := watcher.Add(filepath.Dir(configName)); err != nil {
+		watcher.Close()
+		return nil, err
+	}
+	return watcher, nil
+}
+
+// watchConfig reloads the config on SIGHUP and, if watchFile is set, when the config file changes.
+// It returns when ctx is done.
+func watchConfig(ctx context.Context, s *service.Service, configName string, watchFile bool) {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
+
+	var fileEvents <-chan fsnotify.Event
+	var fileErrors <-chan error
+	if watchFile && configName != "" {
+		watcher, err := newConfigWatcher(configName)
+		if err != nil {
+			log.WithFields(log.Fields{
+				"error": err.Error(),
+				"file":  configName,
+			}).Error("Failed to watch config file, reload with SIGHUP instead")
+		} else {
+			defer watcher.Close()
+			fileEvents, fileErrors = watcher.Events, watcher.Errors
+		}
+	}
+
+	reloader := &configReloader{configName: configName, apply: s.Reload, debounce: reloadDebounce}
+	reloader.run(ctx, hup, fileEvents, fileErrors)
+}
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"code.vegaprotocol.io/priceproxy/config"
+)
+
+const testDebounce = 50 * time.Millisecond
+
+func writeTestConfig(t *testing.T, path, base string) {
+	t.Helper()
+	data := fmt.Sprintf(`server:
+  listen: ":8080"
+sources:
+  - name: src
+    type: http
+    sleepReal: 60
+    price_path: price
+    url:
+      scheme: https
+      host: example.com
+prices:
+  - source: src
+    base: %s
+    quote: USD
+    factor: 1
+`, base)
+	require.NoError(t, os.WriteFile(path, []byte(data), 0o600))
+}
+
+// startReloader runs a reloader of the config file at path until the end of the test, and returns the
+// channels driving it and the channel receiving the applied configs.
+func startReloader(
+	t *testing.T,
+	path string,
+	fileEvents <-chan fsnotify.Event,
+) (chan<- os.Signal, <-chan config.Config) {
+	t.Helper()
+	hup := make(chan os.Signal)
+	applied := make(chan config.Config, 10)
+	reloader := &configReloader{
+		configName: path,
+		apply: func(cfg config.Config) error {
+			applied <- cfg
+			return nil
+		},
+		debounce: testDebounce,
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		reloader.run(ctx, hup, fileEvents, nil)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+	return hup, applied
+}
+
+func TestReloaderSIGHUP(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, []byte("server: [invalid"), 0o600))
+	hup, applied := startReloader(t, path, nil)
+
+	// an invalid config is not applied
+	hup <- syscall.SIGHUP
+	select {
+	case <-applied:
+		t.Fatal("invalid config applied")
+	case <-time.After(2 * testDebounce):
+	}
+
+	writeTestConfig(t, path, "BTC")
+	hup <- syscall.SIGHUP
+	select {
+	case cfg := <-applied:
+		assert.Equal(t, "BTC", cfg.Prices[0].Base)
+	case <-time.After(5 * time.Second):
+		t.Fatal("config not reloaded")
+	}
+}
+
+func TestReloaderDebounce(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	writeTestConfig(t, path, "BTC")
+	fileEvents := make(chan fsnotify.Event)
+	_, applied := startReloader(t, path, fileEvents)
+
+	// events about other files are ignored
+	fileEvents <- fsnotify.Event{Name: filepath.Join(dir, "other.yaml"), Op: fsnotify.Write}
+	select {
+	case <-applied:
+		t.Fatal("config reloaded for another file")
+	case <-time.After(2 * testDebounce):
+	}
+
+	// a burst of events makes a single reload
+	for i := 0; i < 5; i++ {
+		fileEvents <- fsnotify.Event{Name: path, Op: fsnotify.Write}
+		time.Sleep(testDebounce / 5)
+	}
+	select {
+	case <-applied:
+	case <-time.After(5 * time.Second):
+		t.Fatal("config not reloaded")
+	}
+	select {
+	case <-applied:
+		t.Fatal("config reloaded more than once")
+	case <-time.After(2 * testDebounce):
+	}
+}
+
+func TestReloaderEditorRename(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "config.yaml")
+	writeTestConfig(t, path, "BTC")
+
+	watcher, err := newConfigWatcher(path)
+	require.NoError(t, err)
+	defer watcher.Close()
+	_, applied := startReloader(t, path, watcher.Events)
+
+	// editors write a new file next to the config and rename it over the config
+	tmp := filepath.Join(dir, ".config.yaml.swp")
+	writeTestConfig(t, tmp, "ETH")
+	require.NoError(t, os.Rename(tmp, path))
+
+	select {
+	case cfg := <-applied:
+		assert.Equal(t, "ETH", cfg.Prices[0].Base)
+	case <-time.After(5 * time.Second):
+		t.Fatal("config not reloaded")
+	}
+}
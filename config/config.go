@@ -6,47 +6,280 @@ import (
 	"errors"
 	"fmt"
 	"net/url"
+	"os"
+	"path/filepath"
 	"strings"
 	"time"
 
+	"github.com/jinzhu/configor"
 	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v2"
+
+	"code.vegaprotocol.io/priceproxy/expression"
 )
 
 // ServerConfig describes the settings for running the price proxy.
+// The gRPC server is only started when GRPCListen is set.
 type ServerConfig struct {
-	Env       string
-	Listen    string
-	LogFormat string
-	LogLevel  string
+	Env        string
+	Listen     string
+	GRPCListen string
+	LogFormat  string
+	LogLevel   string
 }
 
-// PriceConfig describes one price setting, which uses one source.
+// PriceConfig describes one price setting, which uses one source, or several sources when Aggregate is set,
+// or the updates of another price when Derive is set, or other prices when Synthetic is set.
+// For aggregated, derived and synthetic prices, Source is only the name the price is published under
+// (e.g. "aggregate").
+// MaxAge (seconds) overrides the max age of the source, after which the price is reported as stale.
 type PriceConfig struct {
-	Source        string  `yaml:"source"`
-	Base          string  `yaml:"base"`
-	BaseOverride  string  `yaml:"base_override"`
-	Quote         string  `yaml:"quote"`
-	QuoteOverride string  `yaml:"quote_override"`
-	Factor        float64 `yaml:"factor"`
-	Wander        bool    `yaml:"wander"`
+	Source        string           `yaml:"source"`
+	Base          string           `yaml:"base"`
+	BaseOverride  string           `yaml:"base_override"`
+	Quote         string           `yaml:"quote"`
+	QuoteOverride string           `yaml:"quote_override"`
+	Factor        float64          `yaml:"factor"`
+	Wander        bool             `yaml:"wander"`
+	MaxAge        int              `yaml:"max_age"`
+	Aggregate     *AggregateConfig `yaml:"aggregate"`
+	Derive        *DeriveConfig    `yaml:"derive"`
+	Synthetic     *SyntheticConfig `yaml:"synthetic"`
+}
+
+// AggregateConfig describes how one price is calculated from the prices of several sources.
+// Method is one of AggregateMethodMedian (default), AggregateMethodWeightedMean or AggregateMethodTrimmedMean.
+// Contributions with a LastUpdatedReal older than MaxAge seconds are left out (zero means no limit).
+// Trim is the fraction of contributions dropped from each end for the trimmed mean.
+type AggregateConfig struct {
+	Method  string            `yaml:"method"`
+	MaxAge  int               `yaml:"max_age"`
+	Trim    float64           `yaml:"trim"`
+	Sources []AggregateSource `yaml:"sources"`
+}
+
+// AggregateSource describes one source of an aggregated price. Base and Quote default to the ones of the
+// aggregated price, and Weight defaults to 1.
+type AggregateSource struct {
+	Source string  `yaml:"source"`
+	Base   string  `yaml:"base"`
+	Quote  string  `yaml:"quote"`
+	Weight float64 `yaml:"weight"`
+}
+
+// DeriveConfig describes how one price is calculated from the updates of another price over the last Window
+// seconds. Method is DeriveMethodTWAP (default) or DeriveMethodVWAP. The other price is the one fetched from
+// Source, and Base and Quote default to the ones of the derived price. VWAPs need a source which returns
+// cumulative volumes, i.e. an HTTP/JSON source with a VolumePath (see SourceConfig): the built-in sources
+// return the volume over the last 24 hours, which cannot tell how much was traded between two updates.
+type DeriveConfig struct {
+	Method string `yaml:"method"`
+	Window int    `yaml:"window"`
+	Source string `yaml:"source"`
+	Base   string `yaml:"base"`
+	Quote  string `yaml:"quote"`
+}
+
+// SyntheticConfig describes how one price is calculated from other prices with an arithmetic expression,
+// e.g. "coingecko:bitcoin/usd / coingecko:ethereum/usd * 0.5 + 10" (see package expression). A reference is
+// either another aggregated, derived or synthetic price, named by its source, base and quote, or else a price
+// fetched from a source.
+type SyntheticConfig struct {
+	Expression string `yaml:"expression"`
 }
 
 // SourceConfig describes one source setting (e.g. one API endpoint).
+// Type selects the fetcher used for the source (see SourceType).
 // The URL has "{base}" and "{quote}" replaced at runtime with entries from PriceConfig.
+// SleepWander is the number of seconds between wander steps for prices with Wander set. Zero disables wandering.
+// MaxAge is the number of seconds after which prices from the source are reported as stale. Zero means three
+// times SleepReal.
+//
+// Validation sets the checks made on prices fetched from the source before they are published.
+// Prices not quoted directly by the source are converted from the rates it returned, and from the latest rates
+// of the sources listed in ConversionSources.
+//
+// PricePath, TimestampPath, TimestampFormat, VolumePath and Batch are used by the generic HTTP/JSON source only.
+// PricePath, TimestampPath and VolumePath are JSON path expressions (e.g. "data[0].price") which may also contain
+// "{base}" and "{quote}". TimestampFormat is "unix" (default), "unix_ms", "rfc3339" or a Go time layout.
+// VolumePath points at the cumulative volume traded, as VWAPs use it (see DeriveConfig).
+// With Batch set, one request is made per fetch for all prices, otherwise one request is made per price.
+//
+// HTTP sets up the HTTP client used to fetch from the source.
 type SourceConfig struct {
-	Name           string  `yaml:"name"`
-	URL            url.URL `yaml:"url"`
-	AuthKeyEnvName string  `yaml:"auth_key_env_name"`
-	SleepReal      int     `yaml:"sleepReal"`
+	Name            string  `yaml:"name"`
+	Type            string  `yaml:"type"`
+	URL             url.URL `yaml:"url"`
+	AuthKeyEnvName  string  `yaml:"auth_key_env_name"`
+	SleepReal       int     `yaml:"sleepReal"`
+	SleepWander     int     `yaml:"sleepWander"`
+	MaxAge          int     `yaml:"max_age"`
+	PricePath       string  `yaml:"price_path"`
+	TimestampPath   string  `yaml:"timestamp_path"`
+	TimestampFormat string  `yaml:"timestamp_format"`
+	VolumePath      string  `yaml:"volume_path"`
+	Batch           bool    `yaml:"batch"`
+
+	Validation        *ValidationConfig `yaml:"validation"`
+	ConversionSources []string          `yaml:"conversion_sources"`
+	HTTP              *HTTPClientConfig `yaml:"http"`
+}
+
+// HTTPClientConfig describes the HTTP client used to fetch from one source.
+// Timeout is the number of seconds a request may take, including reading the response (default: 30).
+// Headers are added to every request, and HeadersFromEnv maps header names to the environment variables
+// holding their values, for secrets such as API keys. UserAgent replaces the Go default User-Agent.
+// Proxy is an http://, https:// or socks5:// URL, and takes precedence over the HTTP_PROXY/HTTPS_PROXY
+// environment variables. CABundle is a PEM file with certificates trusted in addition to the system ones.
+// Responses bigger than MaxBodySize bytes are rejected (default: 10 MiB).
+type HTTPClientConfig struct {
+	Timeout        int               `yaml:"timeout"`
+	Headers        map[string]string `yaml:"headers" json:"-"`
+	HeadersFromEnv map[string]string `yaml:"headers_from_env"`
+	UserAgent      string            `yaml:"user_agent"`
+	Proxy          string            `yaml:"proxy"`
+	CABundle       string            `yaml:"ca_bundle"`
+	MaxBodySize    int64             `yaml:"max_body_size"`
+}
+
+// ValidationConfig describes the checks made on fetched prices before they are published.
+// Zero and negative prices are always rejected. A price moving by more than MaxJump percent from the last
+// accepted price, if that was accepted within the last JumpWindow seconds (zero: any time), is rejected unless
+// the move is confirmed by Confirmations consecutive fetches. A zero MaxJump disables the check.
+type ValidationConfig struct {
+	MaxJump       float64 `yaml:"max_jump"`
+	JumpWindow    int     `yaml:"jump_window"`
+	Confirmations int     `yaml:"confirmations"`
 }
 
 type PriceList []PriceConfig
 
+// HistoryConfig describes how much price history is kept in memory, per price.
+// Retention is in seconds. Zero values mean the defaults are used.
+type HistoryConfig struct {
+	Retention int `yaml:"retention"`
+	MaxPoints int `yaml:"max_points"`
+}
+
+// CandlesConfig describes the OHLC candles built for each price. Intervals are in seconds, and MaxCandles is
+// how many candles are kept per price and interval. Zero values mean the defaults are used.
+type CandlesConfig struct {
+	Intervals  []int `yaml:"intervals"`
+	MaxCandles int   `yaml:"max_candles"`
+}
+
+// SnapshotConfig describes where and how often the price board is saved, so that prices survive a restart.
+// Interval is in seconds, zero means the default is used.
+type SnapshotConfig struct {
+	Path     string `yaml:"path"`
+	Interval int    `yaml:"interval"`
+}
+
+// AdminConfig enables the admin API, used to change sources and prices at runtime.
+// Requests must carry the token held in the TokenEnvName environment variable as a bearer token.
+// With WriteConfig set, changes are also saved to the config file, so that they survive a restart.
+type AdminConfig struct {
+	TokenEnvName string `yaml:"token_env_name"`
+	WriteConfig  bool   `yaml:"write_config"`
+}
+
+// HealthConfig describes when the price proxy is ready to serve prices (see GET /readyz). MaxStale is the
+// fraction of prices, from 0 to 1, which may be stale while the price proxy stays ready. Without a health
+// section, DefaultMaxStale is used.
+type HealthConfig struct {
+	MaxStale float64 `yaml:"max_stale"`
+}
+
+// DefaultMaxStale is the fraction of prices which may be stale when no health section is configured.
+const DefaultMaxStale = 0.5
+
+// TracingConfig enables OpenTelemetry tracing. Spans are exported over OTLP/gRPC to the collector at Endpoint
+// (host:port), with TLS unless Insecure is set. ServiceName defaults to "priceproxy". SampleRatio is the
+// fraction of traces kept, from 0 to 1 (zero: all of them).
+type TracingConfig struct {
+	Endpoint    string  `yaml:"endpoint"`
+	Insecure    bool    `yaml:"insecure"`
+	ServiceName string  `yaml:"service_name"`
+	SampleRatio float64 `yaml:"sample_ratio"`
+}
+
+// ScenarioConfig describes a scripted scenario, which overrides the fetched prices of some pairs with a
+// timeline of moves while it runs, e.g. to test bots against a crash. Scenarios are started and stopped
+// through the admin API.
+type ScenarioConfig struct {
+	Name   string                `yaml:"name"`
+	Prices []ScenarioPriceConfig `yaml:"prices"`
+}
+
+// ScenarioPriceConfig describes the timeline of one pair in a scenario. It overrides every price with this
+// source, base and quote. The steps are played in order, starting from the price when the scenario starts,
+// and the pair returns to its real price at the end.
+type ScenarioPriceConfig struct {
+	Source string         `yaml:"source"`
+	Base   string         `yaml:"base"`
+	Quote  string         `yaml:"quote"`
+	Steps  []ScenarioStep `yaml:"steps"`
+}
+
+// ScenarioStep is one step in the timeline of a pair. Action is one of:
+//   - ScenarioActionRamp: move by Change percent, linearly over Duration seconds;
+//   - ScenarioActionGap: move by Change percent at once;
+//   - ScenarioActionHold: stay flat for Duration seconds;
+//   - ScenarioActionOscillate: swing by up to Amplitude percent either way, with a sine wave of Period
+//     seconds, for Duration seconds;
+//   - ScenarioActionReal: return to the real price, linearly over Duration seconds (zero: at once), which
+//     ends the timeline.
+type ScenarioStep struct {
+	Action    string  `yaml:"action"`
+	Change    float64 `yaml:"change"`
+	Duration  int     `yaml:"duration"`
+	Amplitude float64 `yaml:"amplitude"`
+	Period    int     `yaml:"period"`
+}
+
+// Actions of scenario steps.
+const (
+	ScenarioActionRamp      = "ramp"
+	ScenarioActionGap       = "gap"
+	ScenarioActionHold      = "hold"
+	ScenarioActionOscillate = "oscillate"
+	ScenarioActionReal      = "real"
+)
+
+// Source types of the built-in fetchers.
+const (
+	SourceTypeBitstamp      = "bitstamp"
+	SourceTypeCoinGecko     = "coingecko"
+	SourceTypeCoinMarketCap = "coinmarketcap"
+	SourceTypeHTTP          = "http"
+)
+
+// Aggregation methods for aggregated prices.
+const (
+	AggregateMethodMedian       = "median"
+	AggregateMethodWeightedMean = "weighted_mean"
+	AggregateMethodTrimmedMean  = "trimmed_mean"
+)
+
+// Derivation methods for derived prices.
+const (
+	DeriveMethodTWAP = "twap"
+	DeriveMethodVWAP = "vwap"
+)
+
 // Config describes the top level config file format.
 type Config struct {
-	Server  *ServerConfig   `yaml:"server"`
-	Prices  PriceList       `yaml:"prices"`
-	Sources []*SourceConfig `yaml:"sources"`
+	Server   *ServerConfig   `yaml:"server"`
+	Prices   PriceList       `yaml:"prices"`
+	Sources  []*SourceConfig `yaml:"sources"`
+	History  *HistoryConfig  `yaml:"history"`
+	Candles  *CandlesConfig  `yaml:"candles"`
+	Snapshot *SnapshotConfig `yaml:"snapshot"`
+	Admin    *AdminConfig    `yaml:"admin"`
+	Health   *HealthConfig   `yaml:"health"`
+	Tracing  *TracingConfig  `yaml:"tracing"`
+
+	Scenarios []ScenarioConfig `yaml:"scenarios"`
 }
 
 func (pl PriceList) GetBySource(source string) PriceList {
@@ -61,6 +294,142 @@ func (pl PriceList) GetBySource(source string) PriceList {
 	return result
 }
 
+// Components returns the prices fetched from the sources of an aggregated price, the price a derived price
+// is calculated from, or the prices referenced by a synthetic price (see PriceList.Resolve). It returns an
+// empty list for other prices.
+func (pc PriceConfig) Components() PriceList {
+	result := PriceList{}
+	if pc.Derive != nil {
+		return append(result, pc.Derive.PriceConfig(pc))
+	}
+	if pc.Synthetic != nil {
+		references, _ := pc.Synthetic.References()
+		return append(result, references...)
+	}
+	if pc.Aggregate == nil {
+		return result
+	}
+
+	for _, aggSource := range pc.Aggregate.Sources {
+		result = append(result, aggSource.PriceConfig(pc))
+	}
+
+	return result
+}
+
+// Fetched returns true for prices fetched from their source, i.e. neither aggregated, derived nor synthetic.
+func (pc PriceConfig) Fetched() bool {
+	return pc.Aggregate == nil && pc.Derive == nil && pc.Synthetic == nil
+}
+
+// PriceKey identifies a price. Unlike a PriceConfig, whose aggregated, derived and synthetic settings are
+// pointers, it compares equal for equal configs, e.g. when the config is loaded again, so it is used as a map key.
+type PriceKey struct {
+	Source, Base, BaseOverride, Quote, QuoteOverride string
+	Factor                                           float64
+	Wander                                           bool
+	MaxAge                                           int
+	Aggregate, Derive, Synthetic                     string
+}
+
+// Key returns the key identifying the price.
+func (pc PriceConfig) Key() PriceKey {
+	key := PriceKey{
+		Source:        pc.Source,
+		Base:          pc.Base,
+		BaseOverride:  pc.BaseOverride,
+		Quote:         pc.Quote,
+		QuoteOverride: pc.QuoteOverride,
+		Factor:        pc.Factor,
+		Wander:        pc.Wander,
+		MaxAge:        pc.MaxAge,
+	}
+	if pc.Aggregate != nil {
+		key.Aggregate = fmt.Sprintf("%+v", *pc.Aggregate)
+	}
+	if pc.Derive != nil {
+		key.Derive = fmt.Sprintf("%+v", *pc.Derive)
+	}
+	if pc.Synthetic != nil {
+		key.Synthetic = fmt.Sprintf("%+v", *pc.Synthetic)
+	}
+	return key
+}
+
+// Resolve returns the aggregated, derived or synthetic price a reference of a synthetic price (see
+// SyntheticConfig.References) stands for. It returns false when the reference is a price fetched from a source.
+func (pl PriceList) Resolve(reference PriceConfig) (PriceConfig, bool) {
+	for _, price := range pl {
+		if !price.Fetched() && price.Source == reference.Source && price.Base == reference.Base && price.Quote == reference.Quote {
+			return price, true
+		}
+	}
+	return PriceConfig{}, false
+}
+
+// References returns the prices used in the expression of a synthetic price, with a factor of 1.
+func (sc SyntheticConfig) References() (PriceList, error) {
+	expr, err := expression.Parse(sc.Expression)
+	if err != nil {
+		return nil, err
+	}
+
+	result := PriceList{}
+	for _, ref := range expr.References() {
+		result = append(result, PriceConfig{
+			Source: ref.Source,
+			Base:   ref.Base,
+			Quote:  ref.Quote,
+			Factor: 1.0,
+		})
+	}
+	return result, nil
+}
+
+// PriceConfig returns the price a derived price is calculated from.
+func (dc DeriveConfig) PriceConfig(derived PriceConfig) PriceConfig {
+	component := PriceConfig{
+		Source: dc.Source,
+		Base:   dc.Base,
+		Quote:  dc.Quote,
+		Factor: 1.0,
+	}
+	if component.Base == "" {
+		component.Base = derived.Base
+	}
+	if component.Quote == "" {
+		component.Quote = derived.Quote
+	}
+
+	return component
+}
+
+// PriceConfig returns the price fetched from this source for the given aggregated price.
+func (as AggregateSource) PriceConfig(aggregated PriceConfig) PriceConfig {
+	component := PriceConfig{
+		Source: as.Source,
+		Base:   as.Base,
+		Quote:  as.Quote,
+		Factor: 1.0,
+	}
+	if component.Base == "" {
+		component.Base = aggregated.Base
+	}
+	if component.Quote == "" {
+		component.Quote = aggregated.Quote
+	}
+
+	return component
+}
+
+// GetWeight returns the weight of the source, defaulting to 1.
+func (as AggregateSource) GetWeight() float64 {
+	if as.Weight == 0 {
+		return 1.0
+	}
+	return as.Weight
+}
+
 var (
 	// ErrNil indicates that a nil/null pointer was encountered.
 	ErrNil = errors.New("nil pointer")
@@ -72,6 +441,58 @@ var (
 	ErrInvalidValue = errors.New("invalid value")
 )
 
+// LoadConfig reads a config file, and checks it with CheckConfig.
+func LoadConfig(path string) (Config, error) {
+	var cfg Config
+	err := configor.Load(&cfg, path)
+	// https://github.com/jinzhu/configor/issues/40
+	if err != nil && !strings.Contains(err.Error(), "should be struct") {
+		return Config{}, fmt.Errorf("failed to read config: %w", err)
+	}
+
+	if err := CheckConfig(&cfg); err != nil {
+		return Config{}, fmt.Errorf("config checks failed: %w", err)
+	}
+
+	return cfg, nil
+}
+
+// SaveConfig writes a config to a file, replacing it atomically. Comments in the previous file are lost.
+func SaveConfig(path string, cfg Config) error {
+	data, err := yaml.Marshal(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to encode config: %w", err)
+	}
+
+	mode := os.FileMode(0o644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create config file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	if err := tmp.Chmod(mode); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write config file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to replace config file: %w", err)
+	}
+
+	return nil
+}
+
 // CheckConfig checks the config for valid structure and values.
 func CheckConfig(cfg *Config) error {
 	if cfg == nil {
@@ -91,6 +512,28 @@ func CheckConfig(cfg *Config) error {
 		if sourcecfg.SleepReal == 0 {
 			return fmt.Errorf("%s: sleepReal", ErrInvalidValue.Error())
 		}
+		if sourcecfg.SleepWander < 0 {
+			return fmt.Errorf("%s: sleepWander", ErrInvalidValue.Error())
+		}
+		if sourcecfg.MaxAge < 0 {
+			return fmt.Errorf("%s: max_age", ErrInvalidValue.Error())
+		}
+		if v := sourcecfg.Validation; v != nil && (v.MaxJump < 0 || v.JumpWindow < 0 || v.Confirmations < 0) {
+			return fmt.Errorf("%s: validation", ErrInvalidValue.Error())
+		}
+		for _, other := range sourcecfg.ConversionSources {
+			if !HasSource(cfg.Sources, other) {
+				return fmt.Errorf("%s: conversion_sources: %s", ErrInvalidValue.Error(), other)
+			}
+		}
+		if sourcecfg.SourceType() == SourceTypeHTTP && sourcecfg.PricePath == "" {
+			return fmt.Errorf("%s: %s", ErrMissingEmptyConfigSection.Error(), "price_path")
+		}
+		if sourcecfg.HTTP != nil {
+			if err := checkHTTPClientConfig(sourcecfg.HTTP); err != nil {
+				return err
+			}
+		}
 	}
 
 	if cfg.Prices == nil {
@@ -103,11 +546,274 @@ func CheckConfig(cfg *Config) error {
 		if pricecfg.Factor == 0 {
 			return fmt.Errorf("%s: factor", ErrInvalidValue.Error())
 		}
+		if pricecfg.MaxAge < 0 {
+			return fmt.Errorf("%s: max_age", ErrInvalidValue.Error())
+		}
+		if pricecfg.Aggregate != nil {
+			if err := checkAggregateConfig(pricecfg.Aggregate, cfg.Sources); err != nil {
+				return err
+			}
+		}
+		if pricecfg.Derive != nil {
+			if pricecfg.Aggregate != nil {
+				return fmt.Errorf("%s: derive: price is also aggregated", ErrInvalidValue.Error())
+			}
+			if err := checkDeriveConfig(pricecfg.Derive, cfg.Sources); err != nil {
+				return err
+			}
+		}
+		if pricecfg.Synthetic != nil {
+			if pricecfg.Aggregate != nil || pricecfg.Derive != nil {
+				return fmt.Errorf("%s: synthetic: price is also aggregated or derived", ErrInvalidValue.Error())
+			}
+			if err := checkSyntheticConfig(pricecfg.Synthetic, cfg.Prices, cfg.Sources); err != nil {
+				return err
+			}
+		}
+	}
+	if err := checkSyntheticCycles(cfg.Prices); err != nil {
+		return err
+	}
+
+	if cfg.History != nil {
+		if cfg.History.Retention < 0 {
+			return fmt.Errorf("%s: history.retention", ErrInvalidValue.Error())
+		}
+		if cfg.History.MaxPoints < 0 {
+			return fmt.Errorf("%s: history.max_points", ErrInvalidValue.Error())
+		}
+	}
+
+	if cfg.Candles != nil {
+		for _, interval := range cfg.Candles.Intervals {
+			if interval <= 0 {
+				return fmt.Errorf("%s: candles.intervals: %d", ErrInvalidValue.Error(), interval)
+			}
+		}
+		if cfg.Candles.MaxCandles < 0 {
+			return fmt.Errorf("%s: candles.max_candles", ErrInvalidValue.Error())
+		}
+	}
+
+	if cfg.Admin != nil && cfg.Admin.TokenEnvName == "" {
+		return fmt.Errorf("%s: %s", ErrMissingEmptyConfigSection.Error(), "admin.token_env_name")
+	}
+
+	if cfg.Health != nil && (cfg.Health.MaxStale < 0 || cfg.Health.MaxStale > 1) {
+		return fmt.Errorf("%s: health.max_stale", ErrInvalidValue.Error())
+	}
+
+	if cfg.Tracing != nil {
+		if cfg.Tracing.Endpoint == "" {
+			return fmt.Errorf("%s: %s", ErrMissingEmptyConfigSection.Error(), "tracing.endpoint")
+		}
+		if cfg.Tracing.SampleRatio < 0 || cfg.Tracing.SampleRatio > 1 {
+			return fmt.Errorf("%s: tracing.sample_ratio", ErrInvalidValue.Error())
+		}
+	}
+
+	names := make(map[string]bool, len(cfg.Scenarios))
+	for _, scenariocfg := range cfg.Scenarios {
+		if err := checkScenarioConfig(scenariocfg); err != nil {
+			return err
+		}
+		if names[scenariocfg.Name] {
+			return fmt.Errorf("%s: scenarios.name: duplicate: %s", ErrInvalidValue.Error(), scenariocfg.Name)
+		}
+		names[scenariocfg.Name] = true
+	}
+
+	if cfg.Snapshot != nil {
+		if cfg.Snapshot.Path == "" {
+			return fmt.Errorf("%s: %s", ErrMissingEmptyConfigSection.Error(), "snapshot.path")
+		}
+		if cfg.Snapshot.Interval < 0 {
+			return fmt.Errorf("%s: snapshot.interval", ErrInvalidValue.Error())
+		}
+	}
+
+	return nil
+}
+
+func checkDeriveConfig(derive *DeriveConfig, sources []*SourceConfig) error {
+	switch derive.Method {
+	case "", DeriveMethodTWAP, DeriveMethodVWAP:
+	default:
+		return fmt.Errorf("%s: derive.method: %s", ErrInvalidValue.Error(), derive.Method)
+	}
+	if derive.Window <= 0 {
+		return fmt.Errorf("%s: derive.window", ErrInvalidValue.Error())
+	}
+	if !HasSource(sources, derive.Source) {
+		return fmt.Errorf("%s: derive.source: %s", ErrInvalidValue.Error(), derive.Source)
+	}
+	if derive.Method == DeriveMethodVWAP {
+		for _, sourcecfg := range sources {
+			if sourcecfg.Name == derive.Source && (sourcecfg.SourceType() != SourceTypeHTTP || sourcecfg.VolumePath == "") {
+				return fmt.Errorf("%s: derive.source: %s does not return cumulative volumes", ErrInvalidValue.Error(), derive.Source)
+			}
+		}
+	}
+	return nil
+}
+
+func checkScenarioConfig(scenariocfg ScenarioConfig) error {
+	if scenariocfg.Name == "" {
+		return fmt.Errorf("%s: %s", ErrMissingEmptyConfigSection.Error(), "scenarios.name")
+	}
+	if len(scenariocfg.Prices) == 0 {
+		return fmt.Errorf("%s: %s", ErrMissingEmptyConfigSection.Error(), "scenarios.prices")
+	}
+
+	for _, pricecfg := range scenariocfg.Prices {
+		if pricecfg.Source == "" || pricecfg.Base == "" || pricecfg.Quote == "" {
+			return fmt.Errorf("%s: %s", ErrMissingEmptyConfigSection.Error(), "scenarios.prices.source/base/quote")
+		}
+		if len(pricecfg.Steps) == 0 {
+			return fmt.Errorf("%s: %s", ErrMissingEmptyConfigSection.Error(), "scenarios.prices.steps")
+		}
+		for _, step := range pricecfg.Steps {
+			if step.Duration < 0 {
+				return fmt.Errorf("%s: scenarios.prices.steps.duration", ErrInvalidValue.Error())
+			}
+			switch step.Action {
+			case ScenarioActionRamp, ScenarioActionGap:
+				if step.Change <= -100 {
+					return fmt.Errorf("%s: scenarios.prices.steps.change", ErrInvalidValue.Error())
+				}
+			case ScenarioActionOscillate:
+				if step.Amplitude <= 0 || step.Amplitude >= 100 {
+					return fmt.Errorf("%s: scenarios.prices.steps.amplitude", ErrInvalidValue.Error())
+				}
+				if step.Period <= 0 {
+					return fmt.Errorf("%s: scenarios.prices.steps.period", ErrInvalidValue.Error())
+				}
+			case ScenarioActionHold, ScenarioActionReal:
+			default:
+				return fmt.Errorf("%s: scenarios.prices.steps.action: %s", ErrInvalidValue.Error(), step.Action)
+			}
+			if step.Duration == 0 && (step.Action == ScenarioActionRamp || step.Action == ScenarioActionHold || step.Action == ScenarioActionOscillate) {
+				return fmt.Errorf("%s: scenarios.prices.steps.duration", ErrInvalidValue.Error())
+			}
+		}
 	}
+	return nil
+}
 
+func checkSyntheticConfig(synthetic *SyntheticConfig, prices PriceList, sources []*SourceConfig) error {
+	references, err := synthetic.References()
+	if err != nil {
+		return fmt.Errorf("%s: synthetic.expression: %s", ErrInvalidValue.Error(), err.Error())
+	}
+	for _, reference := range references {
+		if _, found := prices.Resolve(reference); !found && !HasSource(sources, reference.Source) {
+			return fmt.Errorf("%s: synthetic.expression: unknown price or source: %s", ErrInvalidValue.Error(), reference.Source)
+		}
+	}
 	return nil
 }
 
+// checkSyntheticCycles checks that no synthetic price depends on itself, through the prices it references.
+func checkSyntheticCycles(prices PriceList) error {
+	const (
+		visiting = 1
+		visited  = 2
+	)
+	state := make(map[*SyntheticConfig]int)
+
+	var visit func(price PriceConfig, path []string) error
+	visit = func(price PriceConfig, path []string) error {
+		path = append(path, price.Source+":"+price.Base+"/"+price.Quote)
+		switch state[price.Synthetic] {
+		case visiting:
+			return fmt.Errorf("%s: synthetic: cycle: %s", ErrInvalidValue.Error(), strings.Join(path, " -> "))
+		case visited:
+			return nil
+		}
+
+		state[price.Synthetic] = visiting
+		for _, component := range price.Components() {
+			if dependency, found := prices.Resolve(component); found && dependency.Synthetic != nil {
+				if err := visit(dependency, path); err != nil {
+					return err
+				}
+			}
+		}
+		state[price.Synthetic] = visited
+		return nil
+	}
+
+	for _, price := range prices {
+		if price.Synthetic != nil {
+			if err := visit(price, nil); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func checkAggregateConfig(agg *AggregateConfig, sources []*SourceConfig) error {
+	switch agg.Method {
+	case "", AggregateMethodMedian, AggregateMethodWeightedMean, AggregateMethodTrimmedMean:
+	default:
+		return fmt.Errorf("%s: aggregate.method: %s", ErrInvalidValue.Error(), agg.Method)
+	}
+	if agg.MaxAge < 0 {
+		return fmt.Errorf("%s: aggregate.max_age", ErrInvalidValue.Error())
+	}
+	if agg.Trim < 0 || agg.Trim >= 0.5 {
+		return fmt.Errorf("%s: aggregate.trim", ErrInvalidValue.Error())
+	}
+	if len(agg.Sources) == 0 {
+		return fmt.Errorf("%s: %s", ErrMissingEmptyConfigSection.Error(), "aggregate.sources")
+	}
+
+	for _, aggSource := range agg.Sources {
+		if aggSource.Weight < 0 {
+			return fmt.Errorf("%s: aggregate.sources.weight", ErrInvalidValue.Error())
+		}
+
+		if !HasSource(sources, aggSource.Source) {
+			return fmt.Errorf("%s: aggregate.sources.source: %s", ErrInvalidValue.Error(), aggSource.Source)
+		}
+	}
+
+	return nil
+}
+
+func checkHTTPClientConfig(httpcfg *HTTPClientConfig) error {
+	if httpcfg.Timeout < 0 {
+		return fmt.Errorf("%s: http.timeout", ErrInvalidValue.Error())
+	}
+	if httpcfg.MaxBodySize < 0 {
+		return fmt.Errorf("%s: http.max_body_size", ErrInvalidValue.Error())
+	}
+	if httpcfg.Proxy != "" {
+		proxyURL, err := url.Parse(httpcfg.Proxy)
+		if err != nil {
+			return fmt.Errorf("%s: http.proxy: %s", ErrInvalidValue.Error(), err.Error())
+		}
+		switch proxyURL.Scheme {
+		case "http", "https", "socks5":
+		default:
+			return fmt.Errorf("%s: http.proxy: unsupported scheme %q", ErrInvalidValue.Error(), proxyURL.Scheme)
+		}
+	}
+
+	return nil
+}
+
+// HasSource returns true if one of the sources has the name.
+func HasSource(sources []*SourceConfig, name string) bool {
+	for _, sourcecfg := range sources {
+		if sourcecfg.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
 // ConfigureLogging configures logging.
 func ConfigureLogging(cfg *ServerConfig) error {
 	if cfg == nil {
@@ -162,6 +868,26 @@ func (ps SourceConfig) String() string {
 		ps.Name, ps.URL.String(), ps.SleepReal)
 }
 
+// SourceType returns the type of the source, which selects the fetcher used for it.
+// Sources without an explicit type fall back to guessing it from the URL host, so older
+// config files keep working.
+func (ps SourceConfig) SourceType() string {
+	if ps.Type != "" {
+		return strings.ToLower(ps.Type)
+	}
+
+	switch {
+	case ps.IsCoinGecko():
+		return SourceTypeCoinGecko
+	case ps.IsCoinMarketCap():
+		return SourceTypeCoinMarketCap
+	case ps.IsBitstamp():
+		return SourceTypeBitstamp
+	default:
+		return SourceTypeHTTP
+	}
+}
+
 func (ps SourceConfig) IsCoinGecko() bool {
 	return strings.Contains(ps.URL.Host, "coingecko.com")
 }
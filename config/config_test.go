@@ -2,10 +2,13 @@ package config_test
 
 import (
 	"net/url"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 
 	"code.vegaprotocol.io/priceproxy/config"
 )
@@ -36,6 +39,16 @@ func TestCheckConfig(t *testing.T) {
 	assert.True(t, strings.HasPrefix(err.Error(), config.ErrInvalidValue.Error()))
 
 	cfg.Sources[0].SleepReal = 1
+	cfg.Sources[0].SleepWander = -1
+	err = config.CheckConfig(&cfg)
+	assert.True(t, strings.HasPrefix(err.Error(), config.ErrInvalidValue.Error()))
+
+	cfg.Sources[0].SleepWander = 0
+	err = config.CheckConfig(&cfg)
+	assert.True(t, strings.HasPrefix(err.Error(), config.ErrMissingEmptyConfigSection.Error()))
+	assert.Contains(t, err.Error(), "price_path")
+
+	cfg.Sources[0].PricePath = "price"
 	err = config.CheckConfig(&cfg)
 	assert.True(t, strings.HasPrefix(err.Error(), config.ErrMissingEmptyConfigSection.Error()))
 
@@ -50,6 +63,211 @@ func TestCheckConfig(t *testing.T) {
 	cfg.Prices[0].Factor = 1
 	err = config.CheckConfig(&cfg)
 	assert.NoError(t, err)
+
+	cfg.Prices[0].Aggregate = &config.AggregateConfig{Method: "mode"}
+	err = config.CheckConfig(&cfg)
+	assert.True(t, strings.HasPrefix(err.Error(), config.ErrInvalidValue.Error()))
+
+	cfg.Prices[0].Aggregate.Method = config.AggregateMethodMedian
+	err = config.CheckConfig(&cfg)
+	assert.True(t, strings.HasPrefix(err.Error(), config.ErrMissingEmptyConfigSection.Error()))
+
+	cfg.Prices[0].Aggregate.Sources = []config.AggregateSource{{Source: "missing"}}
+	err = config.CheckConfig(&cfg)
+	assert.True(t, strings.HasPrefix(err.Error(), config.ErrInvalidValue.Error()))
+
+	cfg.Sources[0].Name = "missing"
+	err = config.CheckConfig(&cfg)
+	assert.NoError(t, err)
+
+	cfg.Prices[0].Derive = &config.DeriveConfig{Method: config.DeriveMethodTWAP, Window: 300, Source: "missing"}
+	err = config.CheckConfig(&cfg)
+	assert.True(t, strings.HasPrefix(err.Error(), config.ErrInvalidValue.Error()))
+
+	cfg.Prices[0].Aggregate = nil
+	err = config.CheckConfig(&cfg)
+	assert.NoError(t, err)
+
+	cfg.Prices[0].Derive.Method = "ema"
+	err = config.CheckConfig(&cfg)
+	assert.True(t, strings.HasPrefix(err.Error(), config.ErrInvalidValue.Error()))
+
+	cfg.Prices[0].Derive = &config.DeriveConfig{Method: config.DeriveMethodVWAP, Source: "missing"}
+	err = config.CheckConfig(&cfg)
+	assert.True(t, strings.HasPrefix(err.Error(), config.ErrInvalidValue.Error()))
+
+	cfg.Prices[0].Derive = &config.DeriveConfig{Window: 300, Source: "unknown"}
+	err = config.CheckConfig(&cfg)
+	assert.True(t, strings.HasPrefix(err.Error(), config.ErrInvalidValue.Error()))
+
+	// VWAPs need cumulative volumes, which only HTTP/JSON sources with a volume_path return
+	cfg.Prices[0].Derive = &config.DeriveConfig{Method: config.DeriveMethodVWAP, Window: 300, Source: "missing"}
+	err = config.CheckConfig(&cfg)
+	assert.True(t, strings.HasPrefix(err.Error(), config.ErrInvalidValue.Error()))
+
+	cfg.Sources[0].VolumePath = "volume"
+	err = config.CheckConfig(&cfg)
+	assert.NoError(t, err)
+
+	cfg.Sources[0].Type = config.SourceTypeBitstamp
+	err = config.CheckConfig(&cfg)
+	assert.True(t, strings.HasPrefix(err.Error(), config.ErrInvalidValue.Error()))
+	cfg.Sources[0].Type = ""
+	cfg.Sources[0].VolumePath = ""
+
+	cfg.Prices[0].Derive = nil
+	err = config.CheckConfig(&cfg)
+	assert.NoError(t, err)
+
+	synthetic := config.PriceConfig{Source: "synthetic", Base: "BTC", Quote: "ETH", Factor: 1}
+	synthetic.Synthetic = &config.SyntheticConfig{Expression: "missing:BTC/USD / (missing:ETH/USD"}
+	cfg.Prices = append(cfg.Prices, synthetic)
+	err = config.CheckConfig(&cfg)
+	assert.True(t, strings.HasPrefix(err.Error(), config.ErrInvalidValue.Error()))
+
+	cfg.Prices[1].Synthetic.Expression = "missing:BTC/USD / unknown:ETH/USD"
+	err = config.CheckConfig(&cfg)
+	assert.True(t, strings.HasPrefix(err.Error(), config.ErrInvalidValue.Error()))
+
+	cfg.Prices[1].Synthetic.Expression = "missing:BTC/USD / missing:ETH/USD * 0.5 + 10"
+	err = config.CheckConfig(&cfg)
+	assert.NoError(t, err)
+
+	// synthetic:BTC/ETH -> other:BTC/ETH -> synthetic:BTC/ETH
+	other := config.PriceConfig{Source: "other", Base: "BTC", Quote: "ETH", Factor: 1}
+	other.Synthetic = &config.SyntheticConfig{Expression: "synthetic:BTC/ETH * 2"}
+	cfg.Prices = append(cfg.Prices, other)
+	cfg.Prices[1].Synthetic.Expression = "other:BTC/ETH + missing:BTC/USD"
+	err = config.CheckConfig(&cfg)
+	assert.True(t, strings.HasPrefix(err.Error(), config.ErrInvalidValue.Error()))
+	assert.Contains(t, err.Error(), "cycle")
+
+	cfg.Prices[2].Synthetic.Expression = "missing:BTC/ETH * 2"
+	err = config.CheckConfig(&cfg)
+	assert.NoError(t, err)
+
+	cfg.Prices = cfg.Prices[:1]
+
+	cfg.Sources[0].HTTP = &config.HTTPClientConfig{Timeout: -1}
+	err = config.CheckConfig(&cfg)
+	assert.True(t, strings.HasPrefix(err.Error(), config.ErrInvalidValue.Error()))
+
+	cfg.Sources[0].HTTP = &config.HTTPClientConfig{Proxy: "ftp://proxy:21"}
+	err = config.CheckConfig(&cfg)
+	assert.True(t, strings.HasPrefix(err.Error(), config.ErrInvalidValue.Error()))
+
+	cfg.Sources[0].HTTP = &config.HTTPClientConfig{Timeout: 10, Proxy: "socks5://localhost:1080"}
+	err = config.CheckConfig(&cfg)
+	assert.NoError(t, err)
+
+	cfg.Candles = &config.CandlesConfig{Intervals: []int{60, 0}}
+	err = config.CheckConfig(&cfg)
+	assert.True(t, strings.HasPrefix(err.Error(), config.ErrInvalidValue.Error()))
+
+	cfg.Candles.Intervals = []int{60, 300, 3600}
+	err = config.CheckConfig(&cfg)
+	assert.NoError(t, err)
+
+	cfg.Snapshot = &config.SnapshotConfig{}
+	err = config.CheckConfig(&cfg)
+	assert.True(t, strings.HasPrefix(err.Error(), config.ErrMissingEmptyConfigSection.Error()))
+
+	cfg.Snapshot.Path = "prices.json"
+	cfg.Snapshot.Interval = -1
+	err = config.CheckConfig(&cfg)
+	assert.True(t, strings.HasPrefix(err.Error(), config.ErrInvalidValue.Error()))
+
+	cfg.Snapshot.Interval = 0
+	err = config.CheckConfig(&cfg)
+	assert.NoError(t, err)
+
+	cfg.Admin = &config.AdminConfig{}
+	err = config.CheckConfig(&cfg)
+	assert.True(t, strings.HasPrefix(err.Error(), config.ErrMissingEmptyConfigSection.Error()))
+
+	cfg.Admin.TokenEnvName = "PRICEPROXY_ADMIN_TOKEN"
+	err = config.CheckConfig(&cfg)
+	assert.NoError(t, err)
+
+	cfg.Health = &config.HealthConfig{MaxStale: 1.5}
+	err = config.CheckConfig(&cfg)
+	assert.True(t, strings.HasPrefix(err.Error(), config.ErrInvalidValue.Error()))
+
+	cfg.Health.MaxStale = 0
+	err = config.CheckConfig(&cfg)
+	assert.NoError(t, err)
+
+	cfg.Tracing = &config.TracingConfig{SampleRatio: 0.1}
+	err = config.CheckConfig(&cfg)
+	assert.True(t, strings.HasPrefix(err.Error(), config.ErrMissingEmptyConfigSection.Error()))
+
+	cfg.Tracing.Endpoint = "localhost:4317"
+	cfg.Tracing.SampleRatio = 2
+	err = config.CheckConfig(&cfg)
+	assert.True(t, strings.HasPrefix(err.Error(), config.ErrInvalidValue.Error()))
+
+	cfg.Tracing.SampleRatio = 0.1
+	err = config.CheckConfig(&cfg)
+	assert.NoError(t, err)
+
+	crash := config.ScenarioConfig{
+		Name: "crash",
+		Prices: []config.ScenarioPriceConfig{{
+			Source: "bitstamp",
+			Base:   "BTC",
+			Quote:  "USD",
+			Steps:  []config.ScenarioStep{{Action: "explode"}},
+		}},
+	}
+	cfg.Scenarios = []config.ScenarioConfig{crash}
+	err = config.CheckConfig(&cfg)
+	assert.True(t, strings.HasPrefix(err.Error(), config.ErrInvalidValue.Error()))
+
+	cfg.Scenarios[0].Prices[0].Steps = []config.ScenarioStep{{Action: config.ScenarioActionOscillate, Duration: 60, Amplitude: 5}}
+	err = config.CheckConfig(&cfg)
+	assert.True(t, strings.HasPrefix(err.Error(), config.ErrInvalidValue.Error()))
+
+	cfg.Scenarios[0].Prices[0].Steps = []config.ScenarioStep{
+		{Action: config.ScenarioActionGap, Change: -30},
+		{Action: config.ScenarioActionHold, Duration: 300},
+		{Action: config.ScenarioActionReal, Duration: 600},
+	}
+	err = config.CheckConfig(&cfg)
+	assert.NoError(t, err)
+
+	cfg.Scenarios = append(cfg.Scenarios, cfg.Scenarios[0])
+	err = config.CheckConfig(&cfg)
+	assert.True(t, strings.HasPrefix(err.Error(), config.ErrInvalidValue.Error()))
+}
+
+func TestSaveConfig(t *testing.T) {
+	cfg := config.Config{
+		Server: &config.ServerConfig{Listen: ":8080"},
+		Sources: []*config.SourceConfig{{
+			Name:              "src",
+			Type:              "http",
+			SleepReal:         60,
+			PricePath:         "price",
+			URL:               url.URL{Scheme: "https", Host: "example.com", Path: "/price"},
+			ConversionSources: []string{},
+		}},
+		Prices: config.PriceList{{Source: "src", Base: "BTC", Quote: "USD", Factor: 1}},
+		Admin:  &config.AdminConfig{TokenEnvName: "PRICEPROXY_ADMIN_TOKEN", WriteConfig: true},
+	}
+
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	require.NoError(t, os.WriteFile(path, nil, 0o600))
+	require.NoError(t, config.SaveConfig(path, cfg))
+
+	info, err := os.Stat(path)
+	require.NoError(t, err)
+	assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+
+	loaded, err := config.LoadConfig(path)
+	require.NoError(t, err)
+	assert.Equal(t, cfg.Prices, loaded.Prices)
+	assert.Equal(t, *cfg.Sources[0], *loaded.Sources[0])
+	assert.Equal(t, *cfg.Admin, *loaded.Admin)
 }
 
 func TestConfigureLogging(t *testing.T) {
@@ -89,3 +307,34 @@ func TestConfigStringFuncs(t *testing.T) {
 	}
 	assert.Equal(t, "{SourceConfig Name:NNN URL:https://example.com/path?a=b&x=y SleepReal:11s}", ps.String())
 }
+
+func TestPriceKey(t *testing.T) {
+	aggregated := func(method string) config.PriceConfig {
+		return config.PriceConfig{
+			Source: "aggregate", Base: "BTC", Quote: "USD", Factor: 1,
+			Aggregate: &config.AggregateConfig{Method: method, Sources: []config.AggregateSource{{Source: "a"}}},
+		}
+	}
+	assert.Equal(t, aggregated("median").Key(), aggregated("median").Key())
+	assert.NotEqual(t, aggregated("median").Key(), aggregated("weighted_mean").Key())
+
+	derived := config.PriceConfig{Source: "twap", Base: "BTC", Quote: "USD", Derive: &config.DeriveConfig{Window: 60}}
+	synthetic := config.PriceConfig{Source: "twap", Base: "BTC", Quote: "USD", Synthetic: &config.SyntheticConfig{}}
+	assert.NotEqual(t, derived.Key(), synthetic.Key())
+
+	fetched := config.PriceConfig{Source: "a", Base: "BTC", Quote: "USD", Factor: 1}
+	assert.Equal(t, config.PriceKey{Source: "a", Base: "BTC", Quote: "USD", Factor: 1}, fetched.Key())
+}
+
+func TestSourceType(t *testing.T) {
+	ps := config.SourceConfig{
+		URL: url.URL{Scheme: "https", Host: "api.coingecko.com"},
+	}
+	assert.Equal(t, config.SourceTypeCoinGecko, ps.SourceType())
+
+	ps.URL.Host = "coingecko-mirror.internal"
+	assert.Equal(t, config.SourceTypeHTTP, ps.SourceType())
+
+	ps.Type = "CoinGecko"
+	assert.Equal(t, config.SourceTypeCoinGecko, ps.SourceType())
+}
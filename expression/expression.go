@@ -0,0 +1,271 @@
+// Package expression parses and evaluates the arithmetic expressions synthetic prices are calculated with,
+// e.g. "coingecko:bitcoin/usd / coingecko:ethereum/usd * 0.5 + 10".
+//
+// Expressions are made of decimal numbers, references to other prices written as source:base/quote, the
+// + - * / operators with the usual precedence, unary minus and parentheses. Names in references may contain
+// letters, digits, "_", "-" and ".", so a "-" right after a reference is part of its quote: separate operators
+// from references with spaces.
+package expression
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// divisionPrecision is the number of decimal places kept when dividing.
+const divisionPrecision = 18
+
+// ErrDivisionByZero is returned when evaluating an expression divides by zero.
+var ErrDivisionByZero = errors.New("division by zero")
+
+// Reference is a price used in an expression.
+type Reference struct {
+	Source string
+	Base   string
+	Quote  string
+}
+
+func (r Reference) String() string {
+	return r.Source + ":" + r.Base + "/" + r.Quote
+}
+
+// Expression is a parsed expression.
+type Expression struct {
+	root       node
+	references []Reference
+}
+
+// Parse parses an expression.
+func Parse(s string) (*Expression, error) {
+	p := &parser{input: s}
+	root, err := p.parseSum()
+	if err != nil {
+		return nil, err
+	}
+	p.skipSpaces()
+	if p.pos < len(p.input) {
+		return nil, fmt.Errorf("unexpected %q at position %d", p.input[p.pos], p.pos)
+	}
+
+	return &Expression{root: root, references: p.references}, nil
+}
+
+// References returns the prices used in the expression, once each, in order of appearance.
+func (e *Expression) References() []Reference {
+	return append([]Reference(nil), e.references...)
+}
+
+// Eval calculates the expression with the given values of the prices it uses. All of them must be given.
+func (e *Expression) Eval(values map[Reference]decimal.Decimal) (decimal.Decimal, error) {
+	return e.root.eval(values)
+}
+
+type node interface {
+	eval(values map[Reference]decimal.Decimal) (decimal.Decimal, error)
+}
+
+type number decimal.Decimal
+
+func (n number) eval(map[Reference]decimal.Decimal) (decimal.Decimal, error) {
+	return decimal.Decimal(n), nil
+}
+
+type reference Reference
+
+func (r reference) eval(values map[Reference]decimal.Decimal) (decimal.Decimal, error) {
+	value, found := values[Reference(r)]
+	if !found {
+		return decimal.Zero, fmt.Errorf("missing value: %s", Reference(r).String())
+	}
+	return value, nil
+}
+
+type negation struct {
+	operand node
+}
+
+func (n negation) eval(values map[Reference]decimal.Decimal) (decimal.Decimal, error) {
+	value, err := n.operand.eval(values)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	return value.Neg(), nil
+}
+
+type operation struct {
+	operator    byte
+	left, right node
+}
+
+func (o operation) eval(values map[Reference]decimal.Decimal) (decimal.Decimal, error) {
+	left, err := o.left.eval(values)
+	if err != nil {
+		return decimal.Zero, err
+	}
+	right, err := o.right.eval(values)
+	if err != nil {
+		return decimal.Zero, err
+	}
+
+	switch o.operator {
+	case '+':
+		return left.Add(right), nil
+	case '-':
+		return left.Sub(right), nil
+	case '*':
+		return left.Mul(right), nil
+	default:
+		if right.IsZero() {
+			return decimal.Zero, ErrDivisionByZero
+		}
+		return left.DivRound(right, divisionPrecision), nil
+	}
+}
+
+// parser is a recursive descent parser, with one function per precedence level.
+type parser struct {
+	input      string
+	pos        int
+	references []Reference
+}
+
+func (p *parser) skipSpaces() {
+	for p.pos < len(p.input) && strings.IndexByte(" \t\n\r", p.input[p.pos]) >= 0 {
+		p.pos++
+	}
+}
+
+// next returns the next character which is not a space, or 0 at the end of the input.
+func (p *parser) next() byte {
+	p.skipSpaces()
+	if p.pos == len(p.input) {
+		return 0
+	}
+	return p.input[p.pos]
+}
+
+// parseSum parses terms separated by + and -.
+func (p *parser) parseSum() (node, error) {
+	left, err := p.parseProduct()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		operator := p.next()
+		if operator != '+' && operator != '-' {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.parseProduct()
+		if err != nil {
+			return nil, err
+		}
+		left = operation{operator: operator, left: left, right: right}
+	}
+}
+
+// parseProduct parses factors separated by * and /.
+func (p *parser) parseProduct() (node, error) {
+	left, err := p.parseFactor()
+	if err != nil {
+		return nil, err
+	}
+	for {
+		operator := p.next()
+		if operator != '*' && operator != '/' {
+			return left, nil
+		}
+		p.pos++
+		right, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+		left = operation{operator: operator, left: left, right: right}
+	}
+}
+
+// parseFactor parses a number, a reference, a negated factor or an expression in parentheses.
+func (p *parser) parseFactor() (node, error) {
+	c := p.next()
+	switch {
+	case c == 0:
+		return nil, fmt.Errorf("unexpected end of expression")
+	case c == '-':
+		p.pos++
+		operand, err := p.parseFactor()
+		if err != nil {
+			return nil, err
+		}
+		return negation{operand: operand}, nil
+	case c == '(':
+		p.pos++
+		inner, err := p.parseSum()
+		if err != nil {
+			return nil, err
+		}
+		if p.next() != ')' {
+			return nil, fmt.Errorf("missing ) at position %d", p.pos)
+		}
+		p.pos++
+		return inner, nil
+	case isNameChar(c):
+		start := p.pos
+		name := p.scanName()
+		if p.pos < len(p.input) && p.input[p.pos] == ':' {
+			return p.parseReference(name)
+		}
+
+		// not a reference: a number, which ends at the first character which is not a digit or "."
+		p.pos = start
+		for p.pos < len(p.input) && (p.input[p.pos] >= '0' && p.input[p.pos] <= '9' || p.input[p.pos] == '.') {
+			p.pos++
+		}
+		value, err := decimal.NewFromString(p.input[start:p.pos])
+		if err != nil {
+			return nil, fmt.Errorf("invalid number or reference %q at position %d", name, start)
+		}
+		return number(value), nil
+	default:
+		return nil, fmt.Errorf("unexpected %q at position %d", c, p.pos)
+	}
+}
+
+// parseReference parses the rest of a reference, after its source name.
+func (p *parser) parseReference(source string) (node, error) {
+	start := p.pos - len(source)
+	p.pos++ // :
+	base := p.scanName()
+	if base == "" || p.pos == len(p.input) || p.input[p.pos] != '/' {
+		return nil, fmt.Errorf("invalid reference at position %d, expected source:base/quote", start)
+	}
+	p.pos++ // /
+	quote := p.scanName()
+	if quote == "" {
+		return nil, fmt.Errorf("invalid reference at position %d, expected source:base/quote", start)
+	}
+
+	ref := Reference{Source: source, Base: base, Quote: quote}
+	found := false
+	for _, r := range p.references {
+		found = found || r == ref
+	}
+	if !found {
+		p.references = append(p.references, ref)
+	}
+	return reference(ref), nil
+}
+
+func (p *parser) scanName() string {
+	start := p.pos
+	for p.pos < len(p.input) && isNameChar(p.input[p.pos]) {
+		p.pos++
+	}
+	return p.input[start:p.pos]
+}
+
+func isNameChar(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || c == '_' || c == '-' || c == '.'
+}
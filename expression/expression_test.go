@@ -0,0 +1,65 @@
+package expression
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEval(t *testing.T) {
+	btc := Reference{Source: "coingecko", Base: "bitcoin", Quote: "usd"}
+	eth := Reference{Source: "coingecko", Base: "ethereum", Quote: "usd"}
+	luna := Reference{Source: "coingecko", Base: "terra-luna-2", Quote: "usd"}
+	values := map[Reference]decimal.Decimal{
+		btc:  decimal.NewFromInt(20000),
+		eth:  decimal.NewFromInt(1000),
+		luna: decimal.RequireFromString("1.5"),
+	}
+
+	for expr, expected := range map[string]string{
+		"coingecko:bitcoin/usd / coingecko:ethereum/usd * 0.5 + 10": "20",
+		"2-1":                            "1",
+		"1 + 2 * 3":                      "7",
+		"(1 + 2) * 3":                    "9",
+		"-(1 + 2) * -3":                  "9",
+		"10 / 4 - 8 / 2 / 2":             "0.5",
+		"coingecko:terra-luna-2/usd * 2": "3",
+		"1 / 3":                          "0.333333333333333333",
+	} {
+		e, err := Parse(expr)
+		require.NoError(t, err, expr)
+		value, err := e.Eval(values)
+		require.NoError(t, err, expr)
+		assert.Equal(t, expected, value.String(), expr)
+	}
+
+	e, err := Parse("coingecko:bitcoin/usd / coingecko:ethereum/usd + coingecko:bitcoin/usd")
+	require.NoError(t, err)
+	assert.Equal(t, []Reference{btc, eth}, e.References())
+
+	e, err = Parse("coingecko:bitcoin/usd / (coingecko:ethereum/usd - 1000)")
+	require.NoError(t, err)
+	_, err = e.Eval(values)
+	assert.ErrorIs(t, err, ErrDivisionByZero)
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, expr := range []string{
+		"",
+		"1 +",
+		"(1 + 2",
+		"1 + 2)",
+		"bitcoin",
+		"coingecko:bitcoin",
+		"coingecko:bitcoin/",
+		"1.2.3",
+		"1 % 2",
+		"2 3",
+		"coingecko:ethereum/usd-coingecko:ethereum/usd", // the quote runs into the next reference
+	} {
+		_, err := Parse(expr)
+		assert.Error(t, err, expr)
+	}
+}
@@ -0,0 +1,127 @@
+package pricing
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"code.vegaprotocol.io/priceproxy/config"
+	"github.com/shopspring/decimal"
+)
+
+// PriceContribution describes what one source contributed to an aggregated price.
+// Used is false when the contribution was left out, e.g. because it is missing or too old.
+type PriceContribution struct {
+	Source          string
+	Base            string
+	Quote           string
+	Price           decimal.Decimal
+	Weight          float64
+	LastUpdatedReal time.Time
+	Used            bool
+}
+
+// aggregate calculates an aggregated price from the latest prices of its components.
+// It returns false when no contribution is usable, in which case only the contributions are updated.
+func aggregate(
+	pricecfg config.PriceConfig,
+	current PriceInfo,
+	componentPrices map[config.PriceKey]PriceInfo,
+	now time.Time,
+) (PriceInfo, bool) {
+	agg := pricecfg.Aggregate
+	maxAge := time.Duration(agg.MaxAge) * time.Second
+
+	result := current
+	result.Contributions = make([]PriceContribution, 0, len(agg.Sources))
+
+	used := []PriceContribution{}
+	scenario := ""
+	for _, aggSource := range agg.Sources {
+		component := aggSource.PriceConfig(pricecfg)
+		contribution := PriceContribution{
+			Source: component.Source,
+			Base:   component.Base,
+			Quote:  component.Quote,
+			Weight: aggSource.GetWeight(),
+		}
+
+		if pi, found := componentPrices[component.Key()]; found {
+			contribution.Price = pi.Price
+			contribution.LastUpdatedReal = pi.LastUpdatedReal
+			contribution.Used = pi.Price.IsPositive() && (maxAge == 0 || now.Sub(pi.LastUpdatedReal) <= maxAge)
+			if contribution.Used && pi.Scenario != "" {
+				scenario = pi.Scenario
+			}
+		}
+
+		if contribution.Used {
+			used = append(used, contribution)
+		}
+		result.Contributions = append(result.Contributions, contribution)
+	}
+
+	price, err := aggregatePrices(agg.Method, agg.Trim, used)
+	if err != nil {
+		return result, false
+	}
+
+	result.Price = price
+	result.Restored = false
+	result.Scenario = scenario
+	result.LastUpdatedReal = time.Time{}
+	for _, contribution := range used {
+		if contribution.LastUpdatedReal.After(result.LastUpdatedReal) {
+			result.LastUpdatedReal = contribution.LastUpdatedReal
+		}
+	}
+	result.LastUpdatedWander = now
+
+	return result, true
+}
+
+func aggregatePrices(method string, trim float64, contributions []PriceContribution) (decimal.Decimal, error) {
+	if len(contributions) == 0 {
+		return decimal.Zero, fmt.Errorf("no contributions")
+	}
+
+	sorted := make([]PriceContribution, len(contributions))
+	copy(sorted, contributions)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Price.LessThan(sorted[j].Price)
+	})
+
+	switch method {
+	case "", config.AggregateMethodMedian:
+		middle := len(sorted) / 2
+		if len(sorted)%2 == 1 {
+			return sorted[middle].Price, nil
+		}
+		return sorted[middle-1].Price.Add(sorted[middle].Price).Div(decimal.NewFromInt(2)), nil
+
+	case config.AggregateMethodWeightedMean:
+		sum, weights := decimal.Zero, decimal.Zero
+		for _, contribution := range sorted {
+			weight := decimal.NewFromFloat(contribution.Weight)
+			sum = sum.Add(contribution.Price.Mul(weight))
+			weights = weights.Add(weight)
+		}
+		if weights.IsZero() {
+			return decimal.Zero, fmt.Errorf("sum of weights is zero")
+		}
+		return sum.DivRound(weights, divisionPrecision), nil
+
+	case config.AggregateMethodTrimmedMean:
+		cut := int(math.Floor(float64(len(sorted)) * trim))
+		kept := sorted[cut : len(sorted)-cut]
+		sum := decimal.Zero
+		for _, contribution := range kept {
+			sum = sum.Add(contribution.Price)
+		}
+		return sum.DivRound(decimal.NewFromInt(int64(len(kept))), divisionPrecision), nil
+
+	default:
+		return decimal.Zero, fmt.Errorf("unknown aggregation method: %s", method)
+	}
+}
@@ -0,0 +1,79 @@
+package pricing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"code.vegaprotocol.io/priceproxy/config"
+)
+
+func TestAggregatePrices(t *testing.T) {
+	contributions := []PriceContribution{
+		{Price: decimal.NewFromInt(101), Weight: 1},
+		{Price: decimal.NewFromInt(100), Weight: 1},
+		{Price: decimal.NewFromInt(500), Weight: 1},
+		{Price: decimal.NewFromInt(99), Weight: 1},
+		{Price: decimal.NewFromInt(102), Weight: 4},
+	}
+
+	price, err := aggregatePrices(config.AggregateMethodMedian, 0, contributions)
+	require.NoError(t, err)
+	assert.Equal(t, "101", price.String())
+
+	price, err = aggregatePrices(config.AggregateMethodMedian, 0, contributions[:4])
+	require.NoError(t, err)
+	assert.Equal(t, "100.5", price.String())
+
+	price, err = aggregatePrices(config.AggregateMethodWeightedMean, 0, contributions)
+	require.NoError(t, err)
+	assert.Equal(t, "151", price.String())
+
+	price, err = aggregatePrices(config.AggregateMethodTrimmedMean, 0.2, contributions)
+	require.NoError(t, err)
+	assert.Equal(t, "101", price.String())
+
+	_, err = aggregatePrices(config.AggregateMethodMedian, 0, nil)
+	assert.Error(t, err)
+}
+
+func TestAggregateSkipsStaleContributions(t *testing.T) {
+	now := time.Now()
+	pricecfg := config.PriceConfig{
+		Source: "aggregate",
+		Base:   "BTC",
+		Quote:  "USD",
+		Factor: 1,
+		Aggregate: &config.AggregateConfig{
+			MaxAge: 60,
+			Sources: []config.AggregateSource{
+				{Source: "a"},
+				{Source: "b", Base: "bitcoin"},
+				{Source: "c"},
+			},
+		},
+	}
+	components := pricecfg.Components()
+	componentPrices := map[config.PriceKey]PriceInfo{
+		components[0].Key(): {Price: decimal.NewFromInt(100), LastUpdatedReal: now},
+		components[1].Key(): {Price: decimal.NewFromInt(200), LastUpdatedReal: now.Add(-time.Hour)},
+	}
+
+	pi, ok := aggregate(pricecfg, PriceInfo{}, componentPrices, now)
+	require.True(t, ok)
+	assert.Equal(t, "100", pi.Price.String())
+	assert.Equal(t, now, pi.LastUpdatedReal)
+	require.Len(t, pi.Contributions, 3)
+	assert.True(t, pi.Contributions[0].Used)
+	assert.Equal(t, "bitcoin", pi.Contributions[1].Base)
+	assert.False(t, pi.Contributions[1].Used)
+	assert.False(t, pi.Contributions[2].Used)
+
+	delete(componentPrices, components[0].Key())
+	pi, ok = aggregate(pricecfg, PriceInfo{Price: decimal.NewFromInt(1)}, componentPrices, now)
+	assert.False(t, ok)
+	assert.Equal(t, "1", pi.Price.String())
+}
@@ -2,109 +2,65 @@ package pricing
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"net/http"
 	"strconv"
 	"strings"
 	"time"
 
 	"code.vegaprotocol.io/priceproxy/config"
-	log "github.com/sirupsen/logrus"
-	"golang.org/x/time/rate"
+	"github.com/shopspring/decimal"
 )
 
-func bitstampStartFetching(
-	board priceBoard,
-	sourcecfg config.SourceConfig,
-) {
-	var (
-		fetchURL        = sourcecfg.URL.String()
-		oneRequestEvery = time.Duration(sourcecfg.SleepReal) * time.Second
-		rateLimiter     = rate.NewLimiter(rate.Every(oneRequestEvery), 1)
-		ctx             = context.Background()
-		err             error
-	)
-
-	log.WithFields(log.Fields{
-		"sourceName":        sourcecfg.Name,
-		"URL":               fetchURL,
-		"rateLimitDuration": oneRequestEvery,
-	}).Infof("Starting bitstamp Fetching\n")
-
-	for {
-		if err = rateLimiter.Wait(ctx); err != nil {
-			log.WithFields(log.Fields{
-				"error":             err.Error(),
-				"sourceName":        sourcecfg.Name,
-				"URL":               fetchURL,
-				"rateLimitDuration": oneRequestEvery,
-			}).Errorln("Rate Limiter Failed. Falling back to Sleep.")
-			// fallback
-			time.Sleep(oneRequestEvery)
-		}
+type bitstampFetcher struct {
+	sourcecfg config.SourceConfig
+	client    *sourceClient
+}
 
-		prices, err := bitstampSingleFetch(fetchURL)
-		if err != nil {
-			log.WithFields(log.Fields{
-				"error":             err.Error(),
-				"sourceName":        sourcecfg.Name,
-				"URL":               fetchURL,
-				"rateLimitDuration": oneRequestEvery,
-			}).Errorf("Retry in %d sec.\n", oneRequestEvery)
-			continue
-		}
+func newBitstampFetcher(sourcecfg config.SourceConfig) (Fetcher, error) {
+	client, err := newSourceClient(sourcecfg)
+	if err != nil {
+		return nil, err
+	}
 
-		for _, price := range board.PriceList(sourcecfg.Name) {
-			var (
-				fetchedTimestamp time.Time = time.Now()
-				fetchedPrice     float64
-			)
+	return &bitstampFetcher{sourcecfg: sourcecfg, client: client}, nil
+}
 
-			if currency := prices.Currency(price.Base, price.Quote); currency != nil {
-				fetchedTimestamp = currency.UnixTimestamp()
-				fetchedPrice = currency.Price()
-			}
+func (f *bitstampFetcher) Fetch(ctx context.Context, priceList config.PriceList) (*FetchResult, error) {
+	prices, err := bitstampSingleFetch(ctx, f.client, f.sourcecfg.URL.String())
+	if err != nil {
+		return nil, err
+	}
 
-			if fetchedPrice == 0 {
-				log.WithFields(log.Fields{
-					"sourceName":     sourcecfg.Name,
-					"base":           price.Base,
-					"quote":          price.Quote,
-					"quote_override": price.QuoteOverride,
-				}).Debug("Quote/Base rate not found directly, trying conversion")
-
-				if currency := prices.Convert(price.Base, price.Quote); currency != nil {
-					fetchedTimestamp = currency.UnixTimestamp()
-					fetchedPrice = currency.Price()
-				}
-			}
+	result := newFetchResult()
+	for _, currency := range prices {
+		result.Rates = append(result.Rates, Rate{
+			Source:          f.sourcecfg.Name,
+			Base:            currency.Base(),
+			Quote:           currency.Quote(),
+			Price:           currency.Price(),
+			LastUpdatedReal: currency.UnixTimestamp(),
+		})
+	}
 
-			if fetchedPrice == 0 {
-				log.WithFields(log.Fields{
-					"sourceName":     sourcecfg.Name,
-					"base":           price.Base,
-					"quote":          price.Quote,
-					"quote_override": price.QuoteOverride,
-				}).Warnf("fetched price in the quote current is 0, consider selecting different quote and overwrite it with the `quote_override` parameter")
+	for _, price := range priceList {
+		if currency := prices.Currency(price.Base, price.Quote); currency != nil {
+			result.Prices[price] = PriceInfo{
+				Price:             currency.Price(),
+				LastUpdatedReal:   currency.UnixTimestamp(),
+				LastUpdatedWander: time.Now().Round(0),
+				Volume:            currency.Volume(),
 			}
-
-			board.UpdatePrice(
-				price,
-				PriceInfo{
-					Price:             fetchedPrice,
-					LastUpdatedReal:   fetchedTimestamp,
-					LastUpdatedWander: time.Now().Round(0),
-				},
-			)
 		}
 	}
+
+	return result, nil
 }
 
 type bitstampCurrencyData struct {
 	Timestamp string `json:"timestamp"`
 	Last      string `json:"last"`
 	Pair      string `json:"pair"`
+	Volume24h string `json:"volume"`
 }
 
 type bitstampFetchData []bitstampCurrencyData
@@ -124,15 +80,25 @@ func (fd bitstampCurrencyData) UnixTimestamp() time.Time {
 	return time.Unix(timestamp, 0)
 }
 
-func (fd bitstampCurrencyData) Price() float64 {
-	price, err := strconv.ParseFloat(fd.Last, 64)
+func (fd bitstampCurrencyData) Price() decimal.Decimal {
+	price, err := decimal.NewFromString(fd.Last)
 	if err != nil {
-		return 0.0
+		return decimal.Zero
 	}
 
 	return price
 }
 
+// Volume returns the volume traded over the last 24 hours, in the base currency.
+func (fd bitstampCurrencyData) Volume() decimal.Decimal {
+	volume, err := decimal.NewFromString(fd.Volume24h)
+	if err != nil {
+		return decimal.Zero
+	}
+
+	return volume
+}
+
 func (fd bitstampCurrencyData) Quote() string {
 	pairSlice := strings.Split(fd.Pair, "/")
 
@@ -152,54 +118,10 @@ func (fd bitstampFetchData) Currency(base, quote string) *bitstampCurrencyData {
 	return nil
 }
 
-func (fd bitstampFetchData) Convert(base, quote string) *bitstampCurrencyData {
-	basePrices := []bitstampCurrencyData{}
-	quotePrices := []bitstampCurrencyData{}
-
-	if currency := fd.Currency(base, quote); currency != nil {
-		return currency
-	}
-
-	for _, price := range fd {
-		if strings.EqualFold(price.Base(), base) {
-			basePrices = append(basePrices, price)
-		}
-
-		if strings.EqualFold(price.Base(), quote) {
-			quotePrices = append(quotePrices, price)
-		}
-	}
-
-	// find common currency and calculate value
-	for _, basePrice := range basePrices {
-		for _, quotePrice := range quotePrices {
-			if strings.EqualFold(basePrice.Quote(), quotePrice.Quote()) {
-				return &bitstampCurrencyData{
-					Timestamp: basePrice.Timestamp,
-					Pair:      fmt.Sprintf("%s/%s", base, quote),
-					Last:      fmt.Sprintf("%f", (basePrice.Price() / quotePrice.Price())),
-				}
-			}
-		}
-	}
-
-	return nil
-}
-
-func bitstampSingleFetch(url string) (bitstampFetchData, error) {
-	resp, err := http.Get(url) // nolint:noctx
-	if err != nil {
-		return nil, fmt.Errorf("failed to get bitstamp data, %w", err)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to get bitstamp data: expected status 200, got %d", resp.StatusCode)
-	}
-
-	defer resp.Body.Close()
+func bitstampSingleFetch(ctx context.Context, client *sourceClient, url string) (bitstampFetchData, error) {
 	var prices bitstampFetchData
-	if err = json.NewDecoder(resp.Body).Decode(&prices); err != nil {
-		return nil, fmt.Errorf("failed to parse bitstamp data, %w", err)
+	if err := client.fetchJSON(ctx, url, &prices); err != nil {
+		return nil, fmt.Errorf("failed to get bitstamp data, %w", err)
 	}
 	return prices, nil
 }
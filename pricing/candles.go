@@ -0,0 +1,147 @@
+package pricing
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"code.vegaprotocol.io/priceproxy/config"
+)
+
+const defaultMaxCandles = 1440
+
+var defaultCandleIntervals = []time.Duration{time.Minute, 5 * time.Minute, time.Hour}
+
+// Candle is the open, high, low and close of a price over one interval, starting at Start.
+// Updates counts the points the candle is built from, Wandered how many of them were wandered and Scenario how
+// many were moved by a scenario.
+// Intervals without any update have no candle.
+type Candle struct {
+	Start    time.Time
+	Open     decimal.Decimal
+	High     decimal.Decimal
+	Low      decimal.Decimal
+	Close    decimal.Decimal
+	Updates  int
+	Wandered int
+	Scenario int
+}
+
+// candleSeries has the latest candles of one price at one interval, oldest first.
+type candleSeries struct {
+	interval   time.Duration
+	maxCandles int
+	candles    []Candle
+}
+
+func newCandleSeries(interval time.Duration, maxCandles int) *candleSeries {
+	return &candleSeries{
+		interval:   interval,
+		maxCandles: maxCandles,
+	}
+}
+
+// add updates the candle of the interval the point falls in. Points older than the latest candle are ignored.
+func (s *candleSeries) add(point PricePoint) {
+	start := point.Time.Truncate(s.interval)
+
+	if n := len(s.candles); n > 0 {
+		last := &s.candles[n-1]
+		if start.Before(last.Start) {
+			return
+		}
+		if start.Equal(last.Start) {
+			if point.Price.GreaterThan(last.High) {
+				last.High = point.Price
+			}
+			if point.Price.LessThan(last.Low) {
+				last.Low = point.Price
+			}
+			last.Close = point.Price
+			last.Updates++
+			if point.Wandered {
+				last.Wandered++
+			}
+			if point.Scenario {
+				last.Scenario++
+			}
+			return
+		}
+	}
+
+	candle := Candle{
+		Start:   start,
+		Open:    point.Price,
+		High:    point.Price,
+		Low:     point.Price,
+		Close:   point.Price,
+		Updates: 1,
+	}
+	if point.Wandered {
+		candle.Wandered = 1
+	}
+	if point.Scenario {
+		candle.Scenario = 1
+	}
+	s.candles = append(s.candles, candle)
+	if len(s.candles) > s.maxCandles {
+		s.candles = append(s.candles[:0], s.candles[len(s.candles)-s.maxCandles:]...)
+	}
+}
+
+// between returns the candles overlapping [from, to], oldest first. A zero from or to leaves that end open.
+func (s *candleSeries) between(from, to time.Time) []Candle {
+	result := []Candle{}
+	for _, candle := range s.candles {
+		if (!from.IsZero() && !candle.Start.Add(s.interval).After(from)) || (!to.IsZero() && candle.Start.After(to)) {
+			continue
+		}
+		result = append(result, candle)
+	}
+	return result
+}
+
+// recordCandles adds a point to the candles of a price, at every interval. It must be called with pricesMu held.
+func (e *engine) recordCandles(pricecfg config.PriceConfig, point PricePoint) {
+	key := pricecfg.Key()
+	series, found := e.candles[key]
+	if !found {
+		series = make(map[time.Duration]*candleSeries, len(e.candleIntervals))
+		for _, interval := range e.candleIntervals {
+			series[interval] = newCandleSeries(interval, e.maxCandles)
+		}
+		e.candles[key] = series
+	}
+
+	for _, s := range series {
+		s.add(point)
+	}
+}
+
+// CandleIntervals returns the intervals candles are built at, shortest first.
+func (e *engine) CandleIntervals() []time.Duration {
+	return append([]time.Duration(nil), e.candleIntervals...)
+}
+
+// GetCandles returns the candles of a price at an interval which overlap from and to (zero for no limit),
+// oldest first.
+func (e *engine) GetCandles(pricecfg config.PriceConfig, interval time.Duration, from, to time.Time) ([]Candle, error) {
+	e.pricesMu.RLock()
+	defer e.pricesMu.RUnlock()
+
+	if _, found := e.prices[pricecfg.Key()]; !found {
+		return nil, fmt.Errorf("price not found: %s", pricecfg.String())
+	}
+
+	series, found := e.candles[pricecfg.Key()][interval]
+	if !found {
+		for _, i := range e.candleIntervals {
+			if i == interval {
+				return []Candle{}, nil
+			}
+		}
+		return nil, fmt.Errorf("no candles at interval %s", interval)
+	}
+	return series.between(from, to), nil
+}
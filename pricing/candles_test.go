@@ -0,0 +1,67 @@
+package pricing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"code.vegaprotocol.io/priceproxy/config"
+)
+
+func TestCandleSeries(t *testing.T) {
+	start := time.Unix(1_700_000_000, 0) // 20s into a minute
+	s := newCandleSeries(time.Minute, 2)
+
+	for i, price := range []int64{10, 12, 8, 11, 20, 30} {
+		s.add(PricePoint{
+			Price:    decimal.NewFromInt(price),
+			Time:     start.Add(time.Duration(i) * 20 * time.Second),
+			Wandered: i%2 == 1,
+		})
+	}
+	// too old for the latest candle
+	s.add(PricePoint{Price: decimal.NewFromInt(1), Time: start})
+
+	// the candle of the first minute was dropped
+	candles := s.between(time.Time{}, time.Time{})
+	require.Len(t, candles, 2)
+	assert.Equal(t, start.Add(40*time.Second), candles[0].Start)
+	assert.Equal(t, []string{"8", "20", "8", "20"}, []string{candles[0].Open.String(), candles[0].High.String(), candles[0].Low.String(), candles[0].Close.String()})
+	assert.Equal(t, 3, candles[0].Updates)
+	assert.Equal(t, 1, candles[0].Wandered)
+	assert.Equal(t, []string{"30", "30", "30", "30"}, []string{candles[1].Open.String(), candles[1].High.String(), candles[1].Low.String(), candles[1].Close.String()})
+	assert.Equal(t, 1, candles[1].Updates)
+	assert.Equal(t, 1, candles[1].Wandered)
+
+	candles = s.between(start.Add(100*time.Second), time.Time{})
+	require.Len(t, candles, 1)
+	assert.Equal(t, start.Add(100*time.Second), candles[0].Start)
+
+	candles = s.between(start.Add(90*time.Second), start.Add(90*time.Second))
+	require.Len(t, candles, 1)
+	assert.Equal(t, start.Add(40*time.Second), candles[0].Start)
+}
+
+func TestGetCandles(t *testing.T) {
+	pricecfg := config.PriceConfig{Source: "a", Base: "BTC", Quote: "USD", Factor: 1}
+	e := NewEngine(config.PriceList{pricecfg}, WithCandles(config.CandlesConfig{Intervals: []int{300, 60, 60}}))
+	assert.Equal(t, []time.Duration{time.Minute, 5 * time.Minute}, e.CandleIntervals())
+
+	e.UpdatePrice(pricecfg, PriceInfo{Price: decimal.NewFromInt(100), LastUpdatedReal: time.Now()})
+	e.UpdatePrice(pricecfg, PriceInfo{Price: decimal.NewFromInt(90), LastUpdatedReal: time.Now()})
+
+	candles, err := e.GetCandles(pricecfg, 5*time.Minute, time.Time{}, time.Time{})
+	require.NoError(t, err)
+	require.NotEmpty(t, candles)
+	last := candles[len(candles)-1]
+	assert.Equal(t, "90", last.Close.String())
+	assert.Equal(t, "90", last.Low.String())
+
+	_, err = e.GetCandles(pricecfg, time.Hour, time.Time{}, time.Time{})
+	assert.Error(t, err)
+	_, err = e.GetCandles(config.PriceConfig{Source: "b"}, time.Minute, time.Time{}, time.Time{})
+	assert.Error(t, err)
+}
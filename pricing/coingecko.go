@@ -2,199 +2,110 @@ package pricing
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"net/http"
 	"strings"
 	"time"
 
 	"code.vegaprotocol.io/priceproxy/config"
+	"github.com/shopspring/decimal"
 	log "github.com/sirupsen/logrus"
-	"golang.org/x/time/rate"
 )
 
 var supportedQuotes = []string{"ETH", "EUR", "USD", "BTC", "DAI"}
 
-func coingeckoStartFetching(
-	board priceBoard,
-	sourcecfg config.SourceConfig,
-) {
-	var (
-		fetchURL        = sourcecfg.URL.String()
-		oneRequestEvery = time.Duration(sourcecfg.SleepReal) * time.Second
-		rateLimiter     = rate.NewLimiter(rate.Every(oneRequestEvery), 1)
-		ctx             = context.Background()
-		err             error
-	)
+type coingeckoFetcher struct {
+	sourcecfg config.SourceConfig
+	client    *sourceClient
+}
+
+func newCoingeckoFetcher(sourcecfg config.SourceConfig) (Fetcher, error) {
+	client, err := newSourceClient(sourcecfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &coingeckoFetcher{sourcecfg: sourcecfg, client: client}, nil
+}
 
-	log.WithFields(log.Fields{
-		"sourceName":        sourcecfg.Name,
-		"URL":               fetchURL,
-		"rateLimitDuration": oneRequestEvery,
-	}).Infof("Starting Coingecko Fetching\n")
+func (f *coingeckoFetcher) Fetch(ctx context.Context, priceList config.PriceList) (*FetchResult, error) {
+	prices, err := coingeckoSingleFetch(ctx, f.client, f.sourcecfg.URL.String())
+	if err != nil {
+		return nil, err
+	}
 
-	for {
-		if err = rateLimiter.Wait(ctx); err != nil {
-			log.WithFields(log.Fields{
-				"error":             err.Error(),
-				"sourceName":        sourcecfg.Name,
-				"URL":               fetchURL,
-				"rateLimitDuration": oneRequestEvery,
-			}).Errorln("Rate Limiter Failed. Falling back to Sleep.")
-			// fallback
-			time.Sleep(oneRequestEvery)
+	result := newFetchResult()
+	for coingeckoBase, coingeckoData := range *prices {
+		for _, quote := range supportedQuotes {
+			if price, found := coingeckoData.QuoteByName(quote); found {
+				result.Rates = append(result.Rates, Rate{
+					Source:          f.sourcecfg.Name,
+					Base:            coingeckoBase,
+					Quote:           quote,
+					Price:           price,
+					LastUpdatedReal: time.Unix(int64(coingeckoData.LastUpdatedAt), 0),
+				})
+			}
 		}
+	}
 
-		prices, err := coingeckoSingleFetch(fetchURL)
-		if err != nil {
-			log.WithFields(log.Fields{
-				"error":             err.Error(),
-				"sourceName":        sourcecfg.Name,
-				"URL":               fetchURL,
-				"rateLimitDuration": oneRequestEvery,
-			}).Errorf("Retry in %d sec.\n", oneRequestEvery)
+	for _, price := range priceList {
+		coingeckoData, found := (*prices)[price.Base]
+		if !found {
+			log.WithContext(ctx).WithFields(log.Fields{
+				"sourceName":     f.sourcecfg.Name,
+				"base":           price.Base,
+				"quote":          price.Quote,
+				"quote_override": price.QuoteOverride,
+			}).Errorf("price not found in the coingecko API")
 			continue
 		}
 
-		for _, price := range board.PriceList(sourcecfg.Name) {
-			priceUpdated := false
-		CoinGeckoLoop:
-			for coingeckoBase, coingeckoData := range *prices {
-				if price.Base == coingeckoBase {
-					var fetchedPrice float64
-
-					switch strings.ToUpper(price.Quote) {
-					case "ETH":
-						fetchedPrice = coingeckoData.ETH
-					case "BTC":
-						fetchedPrice = coingeckoData.BTC
-					case "USD":
-						fetchedPrice = coingeckoData.USD
-					case "EUR":
-						fetchedPrice = coingeckoData.EUR
-					case "DAI":
-						fetchedPrice = coingeckoData.DAI
-					default:
-						log.WithFields(log.Fields{
-							"sourceName":     sourcecfg.Name,
-							"base":           price.Base,
-							"quote":          price.Quote,
-							"quote_override": price.QuoteOverride,
-						}).Errorf("price quote is invalid, got %s, expecting one of %v", price.Quote, supportedQuotes)
-						break CoinGeckoLoop
-					}
-
-					if fetchedPrice == 0 {
-						log.WithFields(log.Fields{
-							"sourceName":     sourcecfg.Name,
-							"base":           price.Base,
-							"quote":          price.Quote,
-							"quote_override": price.QuoteOverride,
-						}).Debug("Quote/Base rate not found directly, trying conversion")
-
-						if convertedPrice := prices.Convert(price.Base, price.Quote); convertedPrice > 0 {
-							fetchedPrice = convertedPrice
-						}
-					}
-
-					if fetchedPrice == 0 {
-						log.WithFields(log.Fields{
-							"sourceName":     sourcecfg.Name,
-							"base":           price.Base,
-							"quote":          price.Quote,
-							"quote_override": price.QuoteOverride,
-						}).Warnf("fetched price in the quote current is 0, consider selecting different quote and overwrite it with the `quote_override` parameter")
-					}
-
-					board.UpdatePrice(
-						price,
-						PriceInfo{
-							Price:             fetchedPrice,
-							LastUpdatedReal:   time.Unix(int64(coingeckoData.LastUpdatedAt), 0),
-							LastUpdatedWander: time.Now().Round(0),
-						},
-					)
-					priceUpdated = true
-				}
-			}
-
-			if !priceUpdated {
-				log.WithFields(log.Fields{
-					"sourceName":     sourcecfg.Name,
-					"base":           price.Base,
-					"quote":          price.Quote,
-					"quote_override": price.QuoteOverride,
-				}).Errorf("price not found in the coingecko API")
+		if fetchedPrice, found := coingeckoData.QuoteByName(price.Quote); found {
+			result.Prices[price] = PriceInfo{
+				Price:             fetchedPrice,
+				LastUpdatedReal:   time.Unix(int64(coingeckoData.LastUpdatedAt), 0),
+				LastUpdatedWander: time.Now().Round(0),
 			}
 		}
 	}
+
+	return result, nil
 }
 
 type coingeckoCurrencyData struct {
-	USD           float64 `json:"usd"`
-	EUR           float64 `json:"eur"`
-	BTC           float64 `json:"btc"`
-	ETH           float64 `json:"eth"`
-	DAI           float64 `json:"dai"`
-	LastUpdatedAt uint64  `json:"last_updated_at"`
+	USD           decimal.NullDecimal `json:"usd"`
+	EUR           decimal.NullDecimal `json:"eur"`
+	BTC           decimal.NullDecimal `json:"btc"`
+	ETH           decimal.NullDecimal `json:"eth"`
+	DAI           decimal.NullDecimal `json:"dai"`
+	LastUpdatedAt uint64              `json:"last_updated_at"`
 }
 
 type coingeckoFetchData map[string]coingeckoCurrencyData
 
-func (fd coingeckoFetchData) Convert(base, quote string) float64 {
-	var baseData *coingeckoCurrencyData
-	var quoteData *coingeckoCurrencyData
-	for name, data := range fd {
-		data := data
-		if strings.EqualFold(base, name) {
-			baseData = &data
-		}
-
-		if strings.EqualFold(quote, name) {
-			quoteData = &data
-		}
-
-		if baseData != nil && quoteData != nil {
-			break
-		}
-	}
-
-	if baseData == nil || quoteData == nil {
-		return 0.0
-	}
-
-	if baseData.USD > 0 && quoteData.USD > 0 {
-		return baseData.USD / quoteData.USD
-	}
-
-	if baseData.EUR > 0 && quoteData.EUR > 0 {
-		return baseData.EUR / quoteData.EUR
-	}
-
-	if baseData.DAI > 0 && quoteData.DAI > 0 {
-		return baseData.DAI / quoteData.DAI
-	}
-
-	if baseData.BTC > 0 && quoteData.BTC > 0 {
-		return baseData.BTC / quoteData.BTC
-	}
-
-	if baseData.ETH > 0 && quoteData.ETH > 0 {
-		return baseData.ETH / quoteData.ETH
+// QuoteByName returns the price in the given quote currency. It returns false if the quote is not one of
+// supportedQuotes, or is missing from the response.
+func (data coingeckoCurrencyData) QuoteByName(quote string) (decimal.Decimal, bool) {
+	var price decimal.NullDecimal
+	switch strings.ToUpper(quote) {
+	case "ETH":
+		price = data.ETH
+	case "BTC":
+		price = data.BTC
+	case "USD":
+		price = data.USD
+	case "EUR":
+		price = data.EUR
+	case "DAI":
+		price = data.DAI
 	}
-
-	return 0.0
+	return price.Decimal, price.Valid
 }
 
-func coingeckoSingleFetch(url string) (*coingeckoFetchData, error) {
-	resp, err := http.Get(url) // nolint:noctx
-	if err != nil {
-		return nil, fmt.Errorf("failed to get coingecko data, %w", err)
-	}
-	defer resp.Body.Close()
+func coingeckoSingleFetch(ctx context.Context, client *sourceClient, url string) (*coingeckoFetchData, error) {
 	var prices coingeckoFetchData
-	if err = json.NewDecoder(resp.Body).Decode(&prices); err != nil {
-		return nil, fmt.Errorf("failed to parse coingecko data, %w", err)
+	if err := client.fetchJSON(ctx, url, &prices); err != nil {
+		return nil, fmt.Errorf("failed to get coingecko data, %w", err)
 	}
 	return &prices, nil
 }
@@ -2,29 +2,30 @@ package pricing
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
-	"net/http"
+	"net/url"
 	"os"
 	"strings"
 	"time"
 
 	"code.vegaprotocol.io/priceproxy/config"
+	"github.com/shopspring/decimal"
 	log "github.com/sirupsen/logrus"
-	"golang.org/x/time/rate"
 )
 
-func coinmarketcapStartFetching(
-	board priceBoard,
-	sourcecfg config.SourceConfig,
-) {
-	var (
-		fetchURL        = sourcecfg.URL
-		oneRequestEvery = time.Duration(sourcecfg.SleepReal) * time.Second
-		rateLimiter     = rate.NewLimiter(rate.Every(oneRequestEvery), 1)
-		ctx             = context.Background()
-		err             error
-	)
+type coinmarketcapFetcher struct {
+	sourcecfg config.SourceConfig
+	fetchURL  url.URL
+	client    *sourceClient
+}
+
+func newCoinmarketcapFetcher(sourcecfg config.SourceConfig) (Fetcher, error) {
+	client, err := newSourceClient(sourcecfg)
+	if err != nil {
+		return nil, err
+	}
+
+	fetchURL := sourcecfg.URL
 
 	apiKey := ""
 	if sourcecfg.AuthKeyEnvName != "" {
@@ -43,96 +44,77 @@ func coinmarketcapStartFetching(
 	fetchURLQuery.Add("CMC_PRO_API_KEY", apiKey)
 	fetchURL.RawQuery = fetchURLQuery.Encode()
 
-	for {
-		if err = rateLimiter.Wait(ctx); err != nil {
-			log.WithFields(log.Fields{
-				"error":             err.Error(),
-				"sourceName":        sourcecfg.Name,
-				"URL":               sourcecfg.URL.String(),
-				"rateLimitDuration": oneRequestEvery,
-			}).Errorln("Rate Limiter Failed. Falling back to Sleep.")
-			// fallback
-			time.Sleep(oneRequestEvery)
+	return &coinmarketcapFetcher{
+		sourcecfg: sourcecfg,
+		fetchURL:  fetchURL,
+		client:    client,
+	}, nil
+}
+
+func (f *coinmarketcapFetcher) Fetch(ctx context.Context, priceList config.PriceList) (*FetchResult, error) {
+	coinmarketcapData, err := coinmarketcapSingleFetch(ctx, f.client, f.fetchURL.String())
+	if err != nil {
+		return nil, err
+	}
+
+	result := newFetchResult()
+	for _, currency := range coinmarketcapData.Data {
+		for qName, qData := range currency.Quote {
+			result.Rates = append(result.Rates, Rate{
+				Source:          f.sourcecfg.Name,
+				Base:            currency.Symbol,
+				Quote:           qName,
+				Price:           qData.Price,
+				LastUpdatedReal: f.parseTime(qData.LastUpdated, currency.Symbol, qName),
+			})
 		}
+	}
 
-		coinmarketcapData, err := coinmarketcapSingleFetch(fetchURL.String())
-		if err != nil {
-			log.WithFields(log.Fields{
-				"error":             err.Error(),
-				"sourceName":        sourcecfg.Name,
-				"URL":               sourcecfg.URL.String(),
-				"rateLimitDuration": oneRequestEvery,
-			}).Errorln("failed to get trading data.")
+	for _, price := range priceList {
+		fetchedCurrency := coinmarketcapData.GetCurrency(price.Base)
+		if fetchedCurrency == nil {
+			log.WithContext(ctx).WithFields(log.Fields{
+				"sourceName":     f.sourcecfg.Name,
+				"base":           price.Base,
+				"quote":          price.Quote,
+				"quote_override": price.QuoteOverride,
+			}).Errorln("price not returned from the API")
 			continue
 		}
 
-		for _, price := range board.PriceList(sourcecfg.Name) {
-			fetchedCurrency := coinmarketcapData.GetCurrency(price.Base)
-			if fetchedCurrency == nil {
-				log.WithFields(log.Fields{
-					"sourceName":     sourcecfg.Name,
-					"base":           price.Base,
-					"quote":          price.Quote,
-					"quote_override": price.QuoteOverride,
-				}).Errorln("price not returned from the API")
-				continue
-			}
-
-			fetchedQuote := fetchedCurrency.QuoteByName(price.Quote)
-			fetchedPrice := 0.0
-			fetchedLastUpdate := fetchedCurrency.LastUpdated
-			if fetchedQuote != nil {
-				fetchedPrice = fetchedQuote.Price
-				fetchedLastUpdate = fetchedQuote.LastUpdated
-			}
-
-			parsedTime, err := time.Parse(time.RFC3339, fetchedLastUpdate)
-			if err != nil {
-				log.WithFields(log.Fields{
-					"error":             err.Error(),
-					"sourceName":        sourcecfg.Name,
-					"base":              price.Base,
-					"quote":             price.Quote,
-					"quote_override":    price.QuoteOverride,
-					"last_updated_time": fetchedLastUpdate,
-				}).Warnf("cannot parse fetched last_updated time with the ISO8601 format")
-			}
-
-			if fetchedPrice == 0 {
-				log.WithFields(log.Fields{
-					"sourceName":     sourcecfg.Name,
-					"base":           price.Base,
-					"quote":          price.Quote,
-					"quote_override": price.QuoteOverride,
-				}).Debug("Quote/Base rate not found directly, trying conversion")
-
-				fetchedPrice = coinmarketcapData.ConvertPrice(price.Base, price.Quote)
+		if fetchedQuote := fetchedCurrency.QuoteByName(price.Quote); fetchedQuote != nil {
+			result.Prices[price] = PriceInfo{
+				Price:             fetchedQuote.Price,
+				LastUpdatedReal:   f.parseTime(fetchedQuote.LastUpdated, price.Base, price.Quote),
+				LastUpdatedWander: time.Now().Round(0),
+				Volume:            fetchedQuote.Volume24h,
 			}
+		}
+	}
 
-			if fetchedPrice == 0 {
-				log.WithFields(log.Fields{
-					"sourceName":     sourcecfg.Name,
-					"base":           price.Base,
-					"quote":          price.Quote,
-					"quote_override": price.QuoteOverride,
-				}).Warnf("fetched price in the quote current is 0, consider selecting different quote and overwrite it with the `quote_override` parameter")
-			}
+	return result, nil
+}
 
-			board.UpdatePrice(
-				price,
-				PriceInfo{
-					Price:             fetchedPrice,
-					LastUpdatedReal:   parsedTime,
-					LastUpdatedWander: time.Now().Round(0),
-				},
-			)
-		}
+func (f *coinmarketcapFetcher) parseTime(lastUpdated, base, quote string) time.Time {
+	parsedTime, err := time.Parse(time.RFC3339, lastUpdated)
+	if err != nil {
+		log.WithFields(log.Fields{
+			"error":             err.Error(),
+			"sourceName":        f.sourcecfg.Name,
+			"base":              base,
+			"quote":             quote,
+			"last_updated_time": lastUpdated,
+		}).Warnf("cannot parse fetched last_updated time with the ISO8601 format")
 	}
+	return parsedTime
 }
 
+// coinmarketcapQuoteData is the price of a currency in one quote currency. Volume24h is the volume traded over
+// the last 24 hours, in the quote currency.
 type coinmarketcapQuoteData struct {
-	Price       float64 `json:"price"`
-	LastUpdated string  `json:"last_updated"`
+	Price       decimal.Decimal `json:"price"`
+	Volume24h   decimal.Decimal `json:"volume_24h"`
+	LastUpdated string          `json:"last_updated"`
 }
 
 type coinmarketcapCurrencyData struct {
@@ -167,45 +149,10 @@ func (currency coinmarketcapCurrencyData) QuoteByName(name string) *coinmarketca
 	return nil
 }
 
-func (data coinmarketcapFetchData) ConvertPrice(base, quote string) float64 {
-	baseCurrency := data.GetCurrency(base)
-	quoteCurrency := data.GetCurrency(quote)
-
-	if baseCurrency == nil || quoteCurrency == nil {
-		return 0.0
-	}
-
-	for qName, qData := range baseCurrency.Quote {
-		// try luck with direct conversion
-		if strings.EqualFold(qName, baseCurrency.Name) || strings.EqualFold(qName, baseCurrency.Slug) || strings.EqualFold(qName, baseCurrency.Symbol) {
-			return qData.Price
-		}
-
-		// conversion by common currency(e.g USD)
-		if commonCurrency := quoteCurrency.QuoteByName(qName); commonCurrency != nil {
-			return qData.Price / commonCurrency.Price
-		}
-
-		// todo: search by ConvertPrice(quote, qName), but may create deadlock...
-		// 	    so we have to impleent timeout + error mechanism here
-	}
-
-	return data.ConvertPrice(quote, base)
-}
-
-func coinmarketcapSingleFetch(url string) (*coinmarketcapFetchData, error) {
-	resp, err := http.Get(url) // nolint:noctx
-	if err != nil {
-		return nil, fmt.Errorf("failed to get coinmarketcap data, %w", err)
-	}
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("failed to get coinmarketcap data: expected status 200, got %d", resp.StatusCode)
-	}
-	defer resp.Body.Close()
+func coinmarketcapSingleFetch(ctx context.Context, client *sourceClient, url string) (*coinmarketcapFetchData, error) {
 	var prices coinmarketcapFetchData
-	if err = json.NewDecoder(resp.Body).Decode(&prices); err != nil {
-		return nil, fmt.Errorf("failed to parse coinmarketcap data, %w", err)
+	if err := client.fetchJSON(ctx, url, &prices); err != nil {
+		return nil, fmt.Errorf("failed to get coinmarketcap data, %w", err)
 	}
 	return &prices, nil
 }
@@ -0,0 +1,134 @@
+package pricing
+
+import (
+	"container/heap"
+	"strings"
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+// maxConversionHops is the longest chain of rates used to convert a price.
+const maxConversionHops = 4
+
+// Rate is one exchange rate returned by a source: one unit of Base costs Price units of Quote.
+type Rate struct {
+	Source          string
+	Base            string
+	Quote           string
+	Price           decimal.Decimal
+	LastUpdatedReal time.Time
+}
+
+// ConversionStep is one rate used to convert a price that was not quoted directly.
+// Inverted is true when the rate was used from quote to base, i.e. its price was inverted.
+type ConversionStep struct {
+	Rate
+	Inverted bool
+}
+
+// rateGraph links currencies by the rates between them, in both directions.
+type rateGraph struct {
+	edges map[string][]ConversionStep
+}
+
+func newRateGraph() *rateGraph {
+	return &rateGraph{
+		edges: make(map[string][]ConversionStep),
+	}
+}
+
+func (g *rateGraph) add(rates ...Rate) {
+	for _, rate := range rates {
+		if !rate.Price.IsPositive() {
+			continue
+		}
+		base, quote := strings.ToLower(rate.Base), strings.ToLower(rate.Quote)
+		if base == quote {
+			continue
+		}
+
+		g.edges[base] = append(g.edges[base], ConversionStep{Rate: rate})
+		g.edges[quote] = append(g.edges[quote], ConversionStep{Rate: rate, Inverted: true})
+	}
+}
+
+// convert finds the price of base in quote. It uses the path with the fewest rates and, among those, the
+// one whose oldest rate is the freshest. The returned timestamp is the one of the oldest rate on the path.
+func (g *rateGraph) convert(base, quote string) (decimal.Decimal, time.Time, []ConversionStep, bool) {
+	base, quote = strings.ToLower(base), strings.ToLower(quote)
+	if base == quote {
+		return decimal.Zero, time.Time{}, nil, false
+	}
+
+	start := &conversionPath{currency: base, price: decimal.NewFromInt(1)}
+	best := map[string]*conversionPath{base: start}
+	queue := &conversionQueue{}
+	heap.Push(queue, start)
+
+	for queue.Len() > 0 {
+		path := heap.Pop(queue).(*conversionPath)
+		if path.currency == quote {
+			return path.price, path.oldest, path.steps, true
+		}
+		if len(path.steps) >= maxConversionHops {
+			continue
+		}
+
+		for _, step := range g.edges[path.currency] {
+			next := strings.ToLower(step.Quote)
+			price := path.price.Mul(step.Price)
+			if step.Inverted {
+				next = strings.ToLower(step.Base)
+				price = path.price.DivRound(step.Price, divisionPrecision)
+			}
+
+			oldest := step.LastUpdatedReal
+			if len(path.steps) > 0 && path.oldest.Before(oldest) {
+				oldest = path.oldest
+			}
+
+			candidate := &conversionPath{
+				currency: next,
+				price:    price,
+				oldest:   oldest,
+				steps:    append(append([]ConversionStep{}, path.steps...), step),
+			}
+			if previous, found := best[next]; found && !candidate.better(previous) {
+				continue
+			}
+			best[next] = candidate
+			heap.Push(queue, candidate)
+		}
+	}
+
+	return decimal.Zero, time.Time{}, nil, false
+}
+
+type conversionPath struct {
+	currency string
+	price    decimal.Decimal
+	oldest   time.Time
+	steps    []ConversionStep
+}
+
+func (p *conversionPath) better(other *conversionPath) bool {
+	if len(p.steps) != len(other.steps) {
+		return len(p.steps) < len(other.steps)
+	}
+	return p.oldest.After(other.oldest)
+}
+
+type conversionQueue []*conversionPath
+
+func (q conversionQueue) Len() int            { return len(q) }
+func (q conversionQueue) Less(i, j int) bool  { return q[i].better(q[j]) }
+func (q conversionQueue) Swap(i, j int)       { q[i], q[j] = q[j], q[i] }
+func (q *conversionQueue) Push(x interface{}) { *q = append(*q, x.(*conversionPath)) }
+func (q *conversionQueue) Pop() interface{} {
+	old := *q
+	n := len(old)
+	item := old[n-1]
+	*q = old[:n-1]
+	return item
+}
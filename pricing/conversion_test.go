@@ -0,0 +1,40 @@
+package pricing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRateGraphConvert(t *testing.T) {
+	now := time.Now()
+	g := newRateGraph()
+	g.add(
+		Rate{Source: "a", Base: "aave", Quote: "USD", Price: decimal.NewFromInt(60), LastUpdatedReal: now},
+		Rate{Source: "a", Base: "ethereum", Quote: "usd", Price: decimal.NewFromInt(1200), LastUpdatedReal: now.Add(-time.Minute)},
+		Rate{Source: "a", Base: "ethereum", Quote: "eur", Price: decimal.NewFromInt(1000), LastUpdatedReal: now.Add(-time.Hour)},
+		Rate{Source: "a", Base: "aave", Quote: "eur", Price: decimal.NewFromInt(50), LastUpdatedReal: now},
+		Rate{Source: "a", Base: "ethereum", Quote: "ETH", Price: decimal.NewFromInt(1), LastUpdatedReal: now},
+		Rate{Source: "b", Base: "IMX", Quote: "BTC", Price: decimal.RequireFromString("0.0001"), LastUpdatedReal: now},
+	)
+
+	price, lastUpdated, path, ok := g.convert("aave", "ETH")
+	require.True(t, ok)
+	assert.Equal(t, "0.05", price.String())
+	assert.Equal(t, now.Add(-time.Minute), lastUpdated)
+	require.Len(t, path, 3)
+	assert.Equal(t, "USD", path[0].Quote)
+	assert.False(t, path[0].Inverted)
+	assert.True(t, path[1].Inverted)
+
+	price, _, path, ok = g.convert("usd", "aave")
+	require.True(t, ok)
+	assert.Equal(t, "0.016666666666666667", price.String())
+	assert.Len(t, path, 1)
+
+	_, _, _, ok = g.convert("aave", "IMX")
+	assert.False(t, ok)
+}
@@ -0,0 +1,133 @@
+package pricing
+
+import (
+	"time"
+
+	"code.vegaprotocol.io/priceproxy/config"
+	"github.com/shopspring/decimal"
+)
+
+// priceSample is one update of the price a derived price is calculated from, at the time it was received.
+type priceSample struct {
+	time   time.Time
+	price  decimal.Decimal
+	volume decimal.Decimal
+}
+
+// priceWindow keeps the updates of a price over a rolling window, oldest first. The last update received
+// before the window is kept too, as it was still the current price when the window started.
+type priceWindow struct {
+	window  time.Duration
+	samples []priceSample
+}
+
+func newPriceWindow(window time.Duration) *priceWindow {
+	return &priceWindow{window: window}
+}
+
+func (w *priceWindow) add(sample priceSample) {
+	w.samples = append(w.samples, sample)
+
+	start := sample.time.Add(-w.window)
+	drop := 0
+	for drop+1 < len(w.samples) && !w.samples[drop+1].time.After(start) {
+		drop++
+	}
+	w.samples = w.samples[drop:]
+}
+
+// twap returns the time-weighted average price over the window ending at now. Each update counts for as long
+// as it was the current price. With a single update, it is the price.
+func (w *priceWindow) twap(now time.Time) (decimal.Decimal, bool) {
+	if len(w.samples) == 0 {
+		return decimal.Zero, false
+	}
+
+	start := now.Add(-w.window)
+	sum, total := decimal.Zero, time.Duration(0)
+	for i, sample := range w.samples {
+		from, to := sample.time, now
+		if from.Before(start) {
+			from = start
+		}
+		if i+1 < len(w.samples) {
+			to = w.samples[i+1].time
+		}
+		if !to.After(from) {
+			continue
+		}
+		sum = sum.Add(sample.price.Mul(decimal.NewFromInt(int64(to.Sub(from)))))
+		total += to.Sub(from)
+	}
+
+	if total == 0 {
+		return w.samples[len(w.samples)-1].price, true
+	}
+	return sum.DivRound(decimal.NewFromInt(int64(total)), divisionPrecision), true
+}
+
+// vwap returns the volume-weighted average price over the window ending at now. Volumes are cumulative, so
+// each update is weighted by the volume traded since the previous one. Updates without a volume, or after which
+// the volume went down (the source reset its count), are left out. It returns false if no volume was traded
+// within the window.
+func (w *priceWindow) vwap(now time.Time) (decimal.Decimal, bool) {
+	start := now.Add(-w.window)
+	sum, volume := decimal.Zero, decimal.Zero
+	for i := 1; i < len(w.samples); i++ {
+		previous, sample := w.samples[i-1], w.samples[i]
+		if sample.time.Before(start) || !previous.volume.IsPositive() {
+			continue
+		}
+		traded := sample.volume.Sub(previous.volume)
+		if !traded.IsPositive() {
+			continue
+		}
+		sum = sum.Add(sample.price.Mul(traded))
+		volume = volume.Add(traded)
+	}
+
+	if volume.IsZero() {
+		return decimal.Zero, false
+	}
+	return sum.DivRound(volume, divisionPrecision), true
+}
+
+// derive adds the latest update of the price a derived price is calculated from to its window, and
+// recalculates it. It returns false when it cannot be calculated, e.g. a VWAP without volumes, in which case
+// the current price is returned. It must be called with pricesMu held.
+func (e *engine) derive(pricecfg config.PriceConfig, now time.Time) (PriceInfo, bool) {
+	key := pricecfg.Key()
+	current := e.prices[key]
+	component, found := e.componentPrices[pricecfg.Derive.PriceConfig(pricecfg).Key()]
+	if !found {
+		return current, false
+	}
+
+	window, found := e.windows[key]
+	if !found {
+		window = newPriceWindow(time.Duration(pricecfg.Derive.Window) * time.Second)
+		e.windows[key] = window
+	}
+	window.add(priceSample{time: now, price: component.Price, volume: component.Volume})
+
+	var (
+		price decimal.Decimal
+		ok    bool
+	)
+	switch pricecfg.Derive.Method {
+	case config.DeriveMethodVWAP:
+		price, ok = window.vwap(now)
+	default:
+		price, ok = window.twap(now)
+	}
+	if !ok {
+		return current, false
+	}
+
+	return PriceInfo{
+		Price:             price,
+		LastUpdatedReal:   component.LastUpdatedReal,
+		LastUpdatedWander: now,
+		Scenario:          component.Scenario,
+	}, true
+}
@@ -0,0 +1,98 @@
+package pricing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"code.vegaprotocol.io/priceproxy/config"
+)
+
+func TestPriceWindow(t *testing.T) {
+	start := time.Unix(1_700_000_000, 0)
+	w := newPriceWindow(time.Minute)
+
+	_, ok := w.twap(start)
+	assert.False(t, ok)
+
+	w.add(priceSample{time: start, price: decimal.NewFromInt(100), volume: decimal.NewFromInt(1)})
+	price, ok := w.twap(start)
+	require.True(t, ok)
+	assert.Equal(t, "100", price.String())
+
+	// 100 for 30s, then 200 for 30s, with 2 traded in between
+	w.add(priceSample{time: start.Add(30 * time.Second), price: decimal.NewFromInt(200), volume: decimal.NewFromInt(3)})
+	price, ok = w.twap(start.Add(time.Minute))
+	require.True(t, ok)
+	assert.Equal(t, "150", price.String())
+	price, ok = w.vwap(start.Add(time.Minute))
+	require.True(t, ok)
+	assert.Equal(t, "200", price.String())
+
+	// the first update was the price until 30s, so it is kept until the window slides past it, and its
+	// volume is the start of the volume traded within the window
+	w.add(priceSample{time: start.Add(80 * time.Second), price: decimal.NewFromInt(400), volume: decimal.NewFromInt(9)})
+	require.Len(t, w.samples, 3)
+	price, ok = w.twap(start.Add(90 * time.Second))
+	require.True(t, ok)
+	assert.Equal(t, decimal.NewFromInt(200*50+400*10).Div(decimal.NewFromInt(60)).StringFixed(6), price.StringFixed(6))
+	price, ok = w.vwap(start.Add(90 * time.Second))
+	require.True(t, ok)
+	assert.Equal(t, decimal.NewFromInt(200*2+400*6).Div(decimal.NewFromInt(8)).String(), price.String())
+
+	// a volume going down is a reset of the count, not a trade
+	w.add(priceSample{time: start.Add(100 * time.Second), price: decimal.NewFromInt(800), volume: decimal.NewFromInt(1)})
+	require.Len(t, w.samples, 3)
+	assert.Equal(t, start.Add(30*time.Second), w.samples[0].time)
+	price, ok = w.vwap(start.Add(100 * time.Second))
+	require.True(t, ok)
+	assert.Equal(t, "400", price.String())
+
+	_, ok = w.vwap(start.Add(10 * time.Minute))
+	assert.False(t, ok)
+}
+
+func TestDerivedPrices(t *testing.T) {
+	spot := config.PriceConfig{Source: "a", Base: "BTC", Quote: "USD", Factor: 1}
+	twap := config.PriceConfig{
+		Source: "twap",
+		Base:   "BTC",
+		Quote:  "USD",
+		Factor: 1,
+		Derive: &config.DeriveConfig{Method: config.DeriveMethodTWAP, Window: 300, Source: "a"},
+	}
+	vwap := config.PriceConfig{
+		Source: "vwap",
+		Base:   "BTC",
+		Quote:  "USD",
+		Factor: 1,
+		Derive: &config.DeriveConfig{Method: config.DeriveMethodVWAP, Window: 300, Source: "a"},
+	}
+	e := NewEngine(config.PriceList{twap, vwap}).(*engine)
+	assert.Equal(t, config.PriceList{spot}, e.PriceList("a"))
+
+	lastUpdated := time.Now().Add(-time.Second).Round(0)
+	e.UpdatePrice(spot, PriceInfo{Price: decimal.NewFromInt(100), LastUpdatedReal: lastUpdated, Volume: decimal.NewFromInt(2)})
+	e.UpdatePrice(spot, PriceInfo{Price: decimal.NewFromInt(200), LastUpdatedReal: lastUpdated, Volume: decimal.NewFromInt(4)})
+	e.UpdatePrice(spot, PriceInfo{Price: decimal.NewFromInt(100), LastUpdatedReal: lastUpdated, Volume: decimal.NewFromInt(6)})
+
+	pi, err := e.GetPrice(vwap)
+	require.NoError(t, err)
+	assert.Equal(t, "150", pi.Price.String())
+	assert.Equal(t, lastUpdated, pi.LastUpdatedReal)
+
+	// the TWAP is between the two updates, depending on how long the first one lasted
+	pi, err = e.GetPrice(twap)
+	require.NoError(t, err)
+	assert.True(t, pi.Price.GreaterThanOrEqual(decimal.NewFromInt(100)) && pi.Price.LessThanOrEqual(decimal.NewFromInt(200)))
+
+	// the spot price is only fetched for the derived prices, so it is not published
+	_, err = e.GetPrice(spot)
+	assert.Error(t, err)
+
+	require.NoError(t, e.Reload(config.PriceList{spot}, nil))
+	assert.Empty(t, e.windows)
+}
@@ -0,0 +1,288 @@
+package pricing
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"code.vegaprotocol.io/priceproxy/config"
+	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"golang.org/x/time/rate"
+)
+
+// Fetcher fetches prices from one upstream source.
+//
+// Fetch is called once every SleepReal seconds with the prices configured for the source. It returns the
+// prices it managed to fetch directly, and every rate found in the upstream response. Prices missing from the
+// result are converted from the rates if possible, and are otherwise left untouched on the price board. Prices
+// in the result are validated as they are, so a fetcher must leave out the prices the source does not quote,
+// rather than return them as zero.
+type Fetcher interface {
+	Fetch(ctx context.Context, prices config.PriceList) (*FetchResult, error)
+}
+
+// FetchResult holds what a Fetcher got from one fetch.
+type FetchResult struct {
+	// Prices has the prices quoted directly by the source.
+	Prices map[config.PriceConfig]PriceInfo
+
+	// Rates has all the rates in the upstream response, used to convert prices not quoted directly.
+	Rates []Rate
+
+	// Errors has the failed requests of a fetch which made one request per price. They are only counted in
+	// the metrics: the fetch itself succeeded.
+	Errors []error
+}
+
+func newFetchResult() *FetchResult {
+	return &FetchResult{
+		Prices: map[config.PriceConfig]PriceInfo{},
+		Rates:  []Rate{},
+	}
+}
+
+// FetcherFactory creates a Fetcher for one source.
+type FetcherFactory func(sourcecfg config.SourceConfig) (Fetcher, error)
+
+var (
+	fetcherFactoriesMu sync.RWMutex
+	fetcherFactories   = map[string]FetcherFactory{
+		config.SourceTypeBitstamp:      newBitstampFetcher,
+		config.SourceTypeCoinGecko:     newCoingeckoFetcher,
+		config.SourceTypeCoinMarketCap: newCoinmarketcapFetcher,
+		config.SourceTypeHTTP:          newHTTPFetcher,
+	}
+)
+
+// RegisterFetcher makes a fetcher available for sources with the given type (see config.SourceConfig.Type).
+// It is meant to be called before the engine starts fetching, e.g. from an init function.
+func RegisterFetcher(sourceType string, factory FetcherFactory) error {
+	if sourceType == "" {
+		return fmt.Errorf("invalid source type: empty")
+	}
+	if factory == nil {
+		return fmt.Errorf("invalid fetcher factory for source type %s: nil", sourceType)
+	}
+
+	fetcherFactoriesMu.Lock()
+	defer fetcherFactoriesMu.Unlock()
+
+	sourceType = strings.ToLower(sourceType)
+	if _, found := fetcherFactories[sourceType]; found {
+		return fmt.Errorf("source type already registered: %s", sourceType)
+	}
+
+	fetcherFactories[sourceType] = factory
+	return nil
+}
+
+// SourceTypes returns the registered source types.
+func SourceTypes() []string {
+	fetcherFactoriesMu.RLock()
+	defer fetcherFactoriesMu.RUnlock()
+
+	result := make([]string, 0, len(fetcherFactories))
+	for sourceType := range fetcherFactories {
+		result = append(result, sourceType)
+	}
+	sort.Strings(result)
+
+	return result
+}
+
+func fetcherFactory(sourceType string) (FetcherFactory, error) {
+	fetcherFactoriesMu.RLock()
+	factory, found := fetcherFactories[sourceType]
+	fetcherFactoriesMu.RUnlock()
+
+	if !found {
+		return nil, fmt.Errorf("unknown source type: %s, expecting one of %v", sourceType, SourceTypes())
+	}
+
+	return factory, nil
+}
+
+func newFetcher(sourcecfg config.SourceConfig) (Fetcher, error) {
+	factory, err := fetcherFactory(sourcecfg.SourceType())
+	if err != nil {
+		return nil, err
+	}
+
+	return factory(sourcecfg)
+}
+
+func startFetching(
+	ctx context.Context,
+	board priceBoard,
+	sourcecfg config.SourceConfig,
+	fetcher Fetcher,
+) {
+	var (
+		oneRequestEvery = time.Duration(sourcecfg.SleepReal) * time.Second
+		rateLimiter     = rate.NewLimiter(rate.Every(oneRequestEvery), 1)
+		err             error
+		failures        int
+	)
+
+	log.WithFields(log.Fields{
+		"sourceName":        sourcecfg.Name,
+		"sourceType":        sourcecfg.SourceType(),
+		"URL":               sourcecfg.URL.String(),
+		"rateLimitDuration": oneRequestEvery,
+	}).Infof("Starting Fetching\n")
+
+	defer log.WithFields(log.Fields{
+		"sourceName": sourcecfg.Name,
+	}).Infof("Stopped Fetching\n")
+
+	for {
+		if err = rateLimiter.Wait(ctx); err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+
+			log.WithFields(log.Fields{
+				"error":             err.Error(),
+				"sourceName":        sourcecfg.Name,
+				"URL":               sourcecfg.URL.String(),
+				"rateLimitDuration": oneRequestEvery,
+			}).Errorln("Rate Limiter Failed. Falling back to Sleep.")
+			// fallback
+			if !sleepContext(ctx, oneRequestEvery) {
+				return
+			}
+		}
+
+		cycleCtx, span := tracer.Start(ctx, "fetch "+sourcecfg.Name, trace.WithAttributes(
+			attribute.String("source.name", sourcecfg.Name),
+			attribute.String("source.type", sourcecfg.SourceType()),
+		))
+		err = fetchCycle(cycleCtx, board, sourcecfg, fetcher)
+		endSpan(span, err)
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			failures++
+			retryIn := retryDelay(err, failures, oneRequestEvery)
+			fields := log.Fields{
+				"error":             err.Error(),
+				"sourceName":        sourcecfg.Name,
+				"URL":               sourcecfg.URL.String(),
+				"rateLimitDuration": oneRequestEvery,
+				"failures":          failures,
+			}
+			var fetchErr *FetchError
+			if errors.As(err, &fetchErr) {
+				fields["errorKind"] = fetchErr.Kind
+				fields["statusCode"] = fetchErr.StatusCode
+			}
+			if retryIn < oneRequestEvery {
+				retryIn = oneRequestEvery
+			}
+			log.WithContext(cycleCtx).WithFields(fields).Errorf("Retry in %s.\n", retryIn)
+
+			// the rate limiter already waits oneRequestEvery before the next fetch
+			if retryIn > oneRequestEvery && !sleepContext(ctx, retryIn-oneRequestEvery) {
+				return
+			}
+			continue
+		}
+		failures = 0
+	}
+}
+
+// fetchCycle fetches the prices of a source once, and updates them on the board. ctx carries the span of the
+// cycle, which gets child spans for the fetch, the conversions and the price updates.
+func fetchCycle(ctx context.Context, board priceBoard, sourcecfg config.SourceConfig, fetcher Fetcher) error {
+	priceList := board.PriceList(sourcecfg.Name)
+	start := time.Now()
+	result, err := fetcher.Fetch(ctx, priceList)
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	board.recordFetch(sourcecfg.Name, time.Since(start), result, err)
+	if err != nil {
+		return err
+	}
+
+	board.UpdateRates(sourcecfg.Name, result.Rates)
+	graph := newRateGraph()
+	graph.add(result.Rates...)
+	for _, other := range sourcecfg.ConversionSources {
+		graph.add(board.GetRates(other)...)
+	}
+
+	for _, price := range priceList {
+		if priceInfo, found := result.Prices[price]; found {
+			// zeros and other bad prices are rejected and counted by the board
+			updatePrice(ctx, board, price, priceInfo)
+			continue
+		}
+
+		log.WithContext(ctx).WithFields(log.Fields{
+			"sourceName":     sourcecfg.Name,
+			"base":           price.Base,
+			"quote":          price.Quote,
+			"quote_override": price.QuoteOverride,
+		}).Debug("Quote/Base rate not found directly, trying conversion")
+
+		_, span := tracer.Start(ctx, "convert", trace.WithAttributes(priceAttributes(price)...))
+		convertedPrice, lastUpdatedReal, path, ok := graph.convert(price.Base, price.Quote)
+		span.SetAttributes(attribute.Bool("converted", ok), attribute.Int("steps", len(path)))
+		span.End()
+		if !ok {
+			log.WithContext(ctx).WithFields(log.Fields{
+				"sourceName":     sourcecfg.Name,
+				"base":           price.Base,
+				"quote":          price.Quote,
+				"quote_override": price.QuoteOverride,
+			}).Warnf("price not found and cannot be converted, consider selecting different quote and overwrite it with the `quote_override` parameter")
+			continue
+		}
+
+		updatePrice(ctx, board, price, PriceInfo{
+			Price:             convertedPrice,
+			LastUpdatedReal:   lastUpdatedReal,
+			LastUpdatedWander: time.Now().Round(0),
+			Conversion:        path,
+		})
+	}
+
+	return nil
+}
+
+// updatePrice updates a price on the board, in a child span of ctx.
+func updatePrice(ctx context.Context, board priceBoard, pricecfg config.PriceConfig, priceInfo PriceInfo) {
+	_, span := tracer.Start(ctx, "UpdatePrice", trace.WithAttributes(priceAttributes(pricecfg)...))
+	span.SetAttributes(attribute.String("price", priceInfo.Price.String()))
+	board.UpdatePrice(pricecfg, priceInfo)
+	span.End()
+}
+
+func priceAttributes(pricecfg config.PriceConfig) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		attribute.String("source.name", pricecfg.Source),
+		attribute.String("price.base", pricecfg.Base),
+		attribute.String("price.quote", pricecfg.Quote),
+	}
+}
+
+// sleepContext sleeps for the given duration, or until ctx is done. It returns false if ctx is done.
+func sleepContext(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
@@ -0,0 +1,93 @@
+package pricing
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"code.vegaprotocol.io/priceproxy/config"
+)
+
+// registerTestFetcher registers a source type until the end of the test.
+func registerTestFetcher(t *testing.T, sourceType string, factory FetcherFactory) {
+	t.Helper()
+	require.NoError(t, RegisterFetcher(sourceType, factory))
+	t.Cleanup(func() {
+		fetcherFactoriesMu.Lock()
+		defer fetcherFactoriesMu.Unlock()
+		delete(fetcherFactories, strings.ToLower(sourceType))
+	})
+}
+
+func TestRegisterFetcher(t *testing.T) {
+	factory := func(sourcecfg config.SourceConfig) (Fetcher, error) {
+		return &staticFetcher{}, nil
+	}
+
+	assert.Error(t, RegisterFetcher("", factory))
+	assert.Error(t, RegisterFetcher("registered", nil))
+	registerTestFetcher(t, "Registered", factory)
+	assert.Error(t, RegisterFetcher("registered", factory))
+	assert.Error(t, RegisterFetcher(config.SourceTypeBitstamp, factory))
+	assert.Contains(t, SourceTypes(), "registered")
+
+	// types are matched whatever their case
+	fetcher, err := newFetcher(config.SourceConfig{Name: "a", Type: "REGISTERED", SleepReal: 1})
+	require.NoError(t, err)
+	assert.IsType(t, &staticFetcher{}, fetcher)
+
+	_, err = newFetcher(config.SourceConfig{Name: "a", Type: "unregistered", SleepReal: 1})
+	assert.Error(t, err)
+
+	e := NewEngine(config.PriceList{})
+	assert.NoError(t, e.AddSource(config.SourceConfig{Name: "a", Type: "Registered", SleepReal: 1}))
+	assert.Error(t, e.AddSource(config.SourceConfig{Name: "b", Type: "unregistered", SleepReal: 1}))
+	assert.Error(t, e.Reload(config.PriceList{}, []config.SourceConfig{{Name: "b", Type: "unregistered", SleepReal: 1}}))
+}
+
+// resultFetcher returns the same result on every fetch.
+type resultFetcher struct {
+	result *FetchResult
+}
+
+func (f *resultFetcher) Fetch(ctx context.Context, prices config.PriceList) (*FetchResult, error) {
+	return f.result, nil
+}
+
+func TestFetchCycleRejectsZeros(t *testing.T) {
+	btc := config.PriceConfig{Source: "a", Base: "BTC", Quote: "EUR", Factor: 1}
+	sourcecfg := config.SourceConfig{Name: "a", Type: "static", SleepReal: 1}
+	now := time.Now().Round(0)
+
+	e := NewEngine(config.PriceList{btc}).(*engine)
+	require.NoError(t, e.AddSource(sourcecfg))
+	e.UpdatePrice(btc, PriceInfo{Price: decimal.NewFromInt(20000), LastUpdatedReal: now.Add(-time.Minute)})
+
+	// the zero quoted directly is rejected, and not replaced by the price converted from the rates
+	fetcher := &resultFetcher{result: &FetchResult{
+		Prices: map[config.PriceConfig]PriceInfo{btc: {Price: decimal.Zero, LastUpdatedReal: now}},
+		Rates: []Rate{
+			{Source: "a", Base: "BTC", Quote: "USD", Price: decimal.NewFromInt(30000), LastUpdatedReal: now},
+			{Source: "a", Base: "EUR", Quote: "USD", Price: decimal.NewFromInt(1), LastUpdatedReal: now},
+		},
+	}}
+	require.NoError(t, fetchCycle(context.Background(), e, sourcecfg, fetcher))
+
+	assert.Equal(t, uint64(1), e.GetRejections(btc).Counts[RejectReasonZero])
+	pi, err := e.GetPrice(btc)
+	require.NoError(t, err)
+	assert.Equal(t, "20000", pi.Price.String())
+
+	// a price missing from the result is converted
+	fetcher.result.Prices = map[config.PriceConfig]PriceInfo{}
+	require.NoError(t, fetchCycle(context.Background(), e, sourcecfg, fetcher))
+	pi, err = e.GetPrice(btc)
+	require.NoError(t, err)
+	assert.Equal(t, "30000", pi.Price.String())
+	assert.Len(t, pi.Conversion, 2)
+}
@@ -0,0 +1,50 @@
+package pricing
+
+import (
+	"time"
+)
+
+// SourceHealth describes the recent fetches from a source. LastSuccess is zero until the first successful fetch.
+// Failures counts the failed fetches since the last successful one, and LastError is the error of the last one.
+type SourceHealth struct {
+	LastSuccess time.Time
+	LastFailure time.Time
+	LastError   string
+	Failures    int
+}
+
+// recordFetch is called by the fetchers after every fetch. A fetch making one request per price is successful
+// if at least one of them is.
+func (e *engine) recordFetch(source string, duration time.Duration, result *FetchResult, err error) {
+	e.metrics.fetched(source, duration, result, err)
+
+	if err == nil && len(result.Errors) > 0 && len(result.Rates) == 0 {
+		err = result.Errors[0]
+	}
+
+	e.ratesMu.Lock()
+	defer e.ratesMu.Unlock()
+
+	health := e.sourceHealth[source]
+	if err == nil {
+		health.LastSuccess = time.Now()
+		health.Failures = 0
+	} else {
+		health.LastFailure = time.Now()
+		health.LastError = err.Error()
+		health.Failures++
+	}
+	e.sourceHealth[source] = health
+}
+
+// GetSourceHealth returns the health of the sources which were fetched at least once.
+func (e *engine) GetSourceHealth() map[string]SourceHealth {
+	e.ratesMu.RLock()
+	defer e.ratesMu.RUnlock()
+
+	result := make(map[string]SourceHealth, len(e.sourceHealth))
+	for name, health := range e.sourceHealth {
+		result[name] = health
+	}
+	return result
+}
@@ -0,0 +1,43 @@
+package pricing
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"code.vegaprotocol.io/priceproxy/config"
+)
+
+func TestSourceHealth(t *testing.T) {
+	e := NewEngine(config.PriceList{}).(*engine)
+	assert.Empty(t, e.GetSourceHealth())
+
+	e.recordFetch("a", time.Second, nil, &FetchError{Kind: FetchErrorServer, Err: errors.New("down")})
+	e.recordFetch("a", time.Second, nil, &FetchError{Kind: FetchErrorServer, Err: errors.New("still down")})
+	health := e.GetSourceHealth()["a"]
+	assert.True(t, health.LastSuccess.IsZero())
+	assert.Equal(t, 2, health.Failures)
+	assert.Equal(t, "5xx error: still down", health.LastError)
+
+	e.recordFetch("a", time.Second, newFetchResult(), nil)
+	health = e.GetSourceHealth()["a"]
+	assert.False(t, health.LastSuccess.IsZero())
+	assert.Equal(t, 0, health.Failures)
+	assert.False(t, health.LastFailure.IsZero())
+
+	// a fetch of one price per request fails if all requests fail
+	result := newFetchResult()
+	result.Errors = append(result.Errors, errors.New("timeout"))
+	e.recordFetch("a", time.Second, result, nil)
+	health = e.GetSourceHealth()["a"]
+	assert.Equal(t, 1, health.Failures)
+	assert.Equal(t, "timeout", health.LastError)
+
+	result.Rates = append(result.Rates, Rate{Source: "a", Base: "BTC", Quote: "USD"})
+	e.recordFetch("a", time.Second, result, nil)
+	require.Contains(t, e.GetSourceHealth(), "a")
+	assert.Equal(t, 0, e.GetSourceHealth()["a"].Failures)
+}
@@ -0,0 +1,85 @@
+package pricing
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+)
+
+const (
+	defaultHistoryRetention = time.Hour
+	defaultHistoryMaxPoints = 3600
+)
+
+// updateKind tells how a price was updated.
+type updateKind int
+
+const (
+	// updateReal is a fetched price, or one aggregated, derived or synthesized from real prices.
+	updateReal updateKind = iota
+	// updateWander is a wandered price.
+	updateWander
+	// updateScenario is a price moved by a scenario, or calculated from one.
+	updateScenario
+)
+
+// PricePoint is one entry in the history of a price.
+// Time is when the price was recorded by the engine. Wandered and Scenario tell wandered points and points moved
+// by a scenario from real ones.
+type PricePoint struct {
+	Price           decimal.Decimal
+	LastUpdatedReal time.Time
+	Time            time.Time
+	Wandered        bool
+	Scenario        bool
+}
+
+// priceHistory is a ring buffer of the latest points of one price.
+type priceHistory struct {
+	points    []PricePoint
+	start     int
+	size      int
+	retention time.Duration
+}
+
+func newPriceHistory(maxPoints int, retention time.Duration) *priceHistory {
+	return &priceHistory{
+		points:    make([]PricePoint, maxPoints),
+		retention: retention,
+	}
+}
+
+func (h *priceHistory) add(point PricePoint) {
+	if len(h.points) == 0 {
+		return
+	}
+
+	end := (h.start + h.size) % len(h.points)
+	h.points[end] = point
+	if h.size < len(h.points) {
+		h.size++
+	} else {
+		h.start = (h.start + 1) % len(h.points)
+	}
+}
+
+// between returns the points recorded within [from, to] and within the retention period, oldest first.
+// A zero from or to leaves that end open.
+func (h *priceHistory) between(from, to time.Time, now time.Time) []PricePoint {
+	if h.retention > 0 {
+		if oldest := now.Add(-h.retention); from.Before(oldest) {
+			from = oldest
+		}
+	}
+
+	result := []PricePoint{}
+	for i := 0; i < h.size; i++ {
+		point := h.points[(h.start+i)%len(h.points)]
+		if point.Time.Before(from) || (!to.IsZero() && point.Time.After(to)) {
+			continue
+		}
+		result = append(result, point)
+	}
+
+	return result
+}
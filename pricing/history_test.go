@@ -0,0 +1,29 @@
+package pricing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPriceHistory(t *testing.T) {
+	now := time.Now()
+	h := newPriceHistory(3, time.Hour)
+
+	for i := 0; i < 5; i++ {
+		h.add(PricePoint{Price: decimal.NewFromInt(int64(i)), Time: now.Add(time.Duration(i-5) * time.Minute)})
+	}
+
+	points := h.between(time.Time{}, time.Time{}, now)
+	assert.Len(t, points, 3)
+	assert.Equal(t, []string{"2", "3", "4"}, []string{points[0].Price.String(), points[1].Price.String(), points[2].Price.String()})
+
+	points = h.between(now.Add(-150*time.Second), now.Add(-90*time.Second), now)
+	assert.Len(t, points, 1)
+	assert.Equal(t, "3", points[0].Price.String())
+
+	points = h.between(time.Time{}, time.Time{}, now.Add(time.Hour-150*time.Second))
+	assert.Len(t, points, 2)
+}
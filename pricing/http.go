@@ -1,32 +1,188 @@
 package pricing
 
 import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
 	"time"
 
 	"code.vegaprotocol.io/priceproxy/config"
+	"github.com/shopspring/decimal"
 	log "github.com/sirupsen/logrus"
 )
 
-func httpStartFetching(
-	board priceBoard,
-	sourcecfg config.SourceConfig,
-) {
-	// TODO: implement when needed
+type httpFetcher struct {
+	sourcecfg config.SourceConfig
+	client    *sourceClient
+}
+
+func newHTTPFetcher(sourcecfg config.SourceConfig) (Fetcher, error) {
+	if sourcecfg.PricePath == "" {
+		return nil, fmt.Errorf("invalid source config for %s: price_path is empty", sourcecfg.Name)
+	}
+
+	client, err := newSourceClient(sourcecfg)
+	if err != nil {
+		return nil, err
+	}
+
+	return &httpFetcher{sourcecfg: sourcecfg, client: client}, nil
+}
+
+func (f *httpFetcher) Fetch(ctx context.Context, priceList config.PriceList) (*FetchResult, error) {
+	var (
+		batchDoc interface{}
+		err      error
+	)
+	if f.sourcecfg.Batch {
+		batchDoc, err = httpSingleFetch(ctx, f.client, f.sourcecfg.URL.String())
+		if err != nil {
+			return nil, fmt.Errorf("failed to get batch data: %w", err)
+		}
+	}
 
-	for {
-		time.Sleep(time.Minute)
+	result := newFetchResult()
+	for _, price := range priceList {
+		doc := batchDoc
+		if !f.sourcecfg.Batch {
+			fetchURL := urlWithBaseQuote(f.sourcecfg.URL, price)
+			doc, err = httpSingleFetch(ctx, f.client, fetchURL.String())
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			if isSourceWide(err) {
+				// no point asking for the other prices, they would fail the same way
+				return nil, err
+			}
+			if err != nil {
+				log.WithContext(ctx).WithFields(log.Fields{
+					"error":          err.Error(),
+					"sourceName":     f.sourcecfg.Name,
+					"URL":            fetchURL.String(),
+					"base":           price.Base,
+					"quote":          price.Quote,
+					"quote_override": price.QuoteOverride,
+				}).Errorln("failed to get price data.")
+				result.Errors = append(result.Errors, err)
+				continue
+			}
+		}
 
-		log.WithFields(log.Fields{
-			"sourceName": sourcecfg.Name,
-		}).Errorf("You are trying to use the fetcher which is not implemented yet. Try to use different one: bitstamp, coingecko, coinmarketcap")
+		priceInfo, err := httpExtractPrice(f.sourcecfg, price, doc)
+		if err != nil {
+			log.WithContext(ctx).WithFields(log.Fields{
+				"error":          err.Error(),
+				"sourceName":     f.sourcecfg.Name,
+				"base":           price.Base,
+				"quote":          price.Quote,
+				"quote_override": price.QuoteOverride,
+				"price_path":     f.sourcecfg.PricePath,
+				"timestamp_path": f.sourcecfg.TimestampPath,
+			}).Errorln("failed to extract price from the response")
+			continue
+		}
+
+		result.Prices[price] = priceInfo
+		result.Rates = append(result.Rates, Rate{
+			Source:          f.sourcecfg.Name,
+			Base:            price.Base,
+			Quote:           price.Quote,
+			Price:           priceInfo.Price,
+			LastUpdatedReal: priceInfo.LastUpdatedReal,
+		})
 	}
+
+	return result, nil
 }
 
-// func urlWithBaseQuote(u url.URL, pricecfg config.PriceConfig) *url.URL {
-// 	result := u
-// 	result.Path = strings.Replace(result.Path, "{base}", pricecfg.Base, 1)
-// 	result.Path = strings.Replace(result.Path, "{quote}", pricecfg.Quote, 1)
-// 	result.RawQuery = strings.Replace(result.RawQuery, "{base}", pricecfg.Base, 1)
-// 	result.RawQuery = strings.Replace(result.RawQuery, "{quote}", pricecfg.Quote, 1)
-// 	return &result
-// }
+// httpExtractPrice pulls the price and (optionally) its timestamp and volume for one price out of a decoded JSON
+// response.
+func httpExtractPrice(sourcecfg config.SourceConfig, pricecfg config.PriceConfig, doc interface{}) (PriceInfo, error) {
+	priceValue, err := jsonPathLookup(doc, withBaseQuote(sourcecfg.PricePath, pricecfg))
+	if err != nil {
+		return PriceInfo{}, fmt.Errorf("failed to find price: %w", err)
+	}
+
+	fetchedPrice, err := jsonDecimal(priceValue)
+	if err != nil {
+		return PriceInfo{}, fmt.Errorf("failed to parse price: %w", err)
+	}
+
+	fetchedTimestamp := time.Now().Round(0)
+	if sourcecfg.TimestampPath != "" {
+		timestampValue, err := jsonPathLookup(doc, withBaseQuote(sourcecfg.TimestampPath, pricecfg))
+		if err != nil {
+			return PriceInfo{}, fmt.Errorf("failed to find timestamp: %w", err)
+		}
+
+		fetchedTimestamp, err = parseTimestamp(timestampValue, sourcecfg.TimestampFormat)
+		if err != nil {
+			return PriceInfo{}, fmt.Errorf("failed to parse timestamp: %w", err)
+		}
+	}
+
+	fetchedVolume := decimal.Zero
+	if sourcecfg.VolumePath != "" {
+		volumeValue, err := jsonPathLookup(doc, withBaseQuote(sourcecfg.VolumePath, pricecfg))
+		if err != nil {
+			return PriceInfo{}, fmt.Errorf("failed to find volume: %w", err)
+		}
+
+		fetchedVolume, err = jsonDecimal(volumeValue)
+		if err != nil {
+			return PriceInfo{}, fmt.Errorf("failed to parse volume: %w", err)
+		}
+	}
+
+	return PriceInfo{
+		Price:             fetchedPrice,
+		LastUpdatedReal:   fetchedTimestamp,
+		LastUpdatedWander: time.Now().Round(0),
+		Volume:            fetchedVolume,
+	}, nil
+}
+
+func parseTimestamp(value interface{}, format string) (time.Time, error) {
+	switch strings.ToLower(format) {
+	case "", "unix", "unix_ms":
+		timestamp, err := jsonDecimal(value)
+		if err != nil {
+			return time.Time{}, err
+		}
+		if strings.EqualFold(format, "unix_ms") {
+			return time.UnixMilli(timestamp.IntPart()), nil
+		}
+		return time.Unix(timestamp.IntPart(), 0), nil
+	case "rfc3339":
+		format = time.RFC3339
+	}
+
+	str, ok := value.(string)
+	if !ok {
+		return time.Time{}, fmt.Errorf("timestamp is not a string: %v", value)
+	}
+
+	return time.Parse(format, str)
+}
+
+func httpSingleFetch(ctx context.Context, client *sourceClient, url string) (interface{}, error) {
+	var doc interface{}
+	if err := client.fetchJSON(ctx, url, &doc); err != nil {
+		return nil, err
+	}
+	return doc, nil
+}
+
+func withBaseQuote(s string, pricecfg config.PriceConfig) string {
+	s = strings.ReplaceAll(s, "{base}", pricecfg.Base)
+	return strings.ReplaceAll(s, "{quote}", pricecfg.Quote)
+}
+
+func urlWithBaseQuote(u url.URL, pricecfg config.PriceConfig) *url.URL {
+	result := u
+	result.Path = withBaseQuote(result.Path, pricecfg)
+	result.RawPath = ""
+	result.RawQuery = withBaseQuote(result.RawQuery, pricecfg)
+	return &result
+}
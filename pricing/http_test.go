@@ -0,0 +1,77 @@
+package pricing
+
+import (
+	"encoding/json"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"code.vegaprotocol.io/priceproxy/config"
+)
+
+func decodeJSON(t *testing.T, s string) interface{} {
+	t.Helper()
+
+	var doc interface{}
+	decoder := json.NewDecoder(strings.NewReader(s))
+	decoder.UseNumber()
+	require.NoError(t, decoder.Decode(&doc))
+	return doc
+}
+
+func TestURLWithBaseQuote(t *testing.T) {
+	u := url.URL{
+		Scheme:   "https",
+		Host:     "example.com",
+		Path:     "/api/v2/ticker/{base}{quote}/",
+		RawQuery: "base={base}&symbols={quote}",
+	}
+	pricecfg := config.PriceConfig{Base: "btc", Quote: "usd"}
+
+	assert.Equal(t, "https://example.com/api/v2/ticker/btcusd/?base=btc&symbols=usd", urlWithBaseQuote(u, pricecfg).String())
+	assert.Equal(t, "/api/v2/ticker/{base}{quote}/", u.Path)
+}
+
+func TestHTTPExtractPrice(t *testing.T) {
+	pricecfg := config.PriceConfig{Base: "BTC", Quote: "USD"}
+
+	doc := decodeJSON(t, `{"data": [{"symbol": "BTC", "quote": {"USD": {"price": "20123.45", "ts": 1668000000}}}]}`)
+	sourcecfg := config.SourceConfig{
+		PricePath:     "$.data[0].quote.{quote}.price",
+		TimestampPath: "data.0.quote.{quote}.ts",
+	}
+	pi, err := httpExtractPrice(sourcecfg, pricecfg, doc)
+	require.NoError(t, err)
+	assert.Equal(t, "20123.45", pi.Price.String())
+	assert.Equal(t, time.Unix(1668000000, 0), pi.LastUpdatedReal)
+
+	doc = decodeJSON(t, `{"date": "2022-11-09", "rates": {"usd": 1.01}}`)
+	sourcecfg = config.SourceConfig{
+		PricePath:       "rates.{quote}",
+		TimestampPath:   "date",
+		TimestampFormat: "2006-01-02",
+	}
+	pi, err = httpExtractPrice(sourcecfg, pricecfg, doc)
+	require.NoError(t, err)
+	assert.Equal(t, "1.01", pi.Price.String())
+	assert.Equal(t, time.Date(2022, 11, 9, 0, 0, 0, 0, time.UTC), pi.LastUpdatedReal)
+
+	// small prices keep all their digits
+	doc = decodeJSON(t, `{"rates": {"usd": 0.000000012345678901234567}}`)
+	sourcecfg = config.SourceConfig{PricePath: "rates.{quote}"}
+	pi, err = httpExtractPrice(sourcecfg, pricecfg, doc)
+	require.NoError(t, err)
+	assert.Equal(t, "0.000000012345678901234567", pi.Price.String())
+
+	sourcecfg.PricePath = "rates.EUR"
+	_, err = httpExtractPrice(sourcecfg, pricecfg, doc)
+	assert.Error(t, err)
+
+	sourcecfg.PricePath = "date"
+	_, err = httpExtractPrice(sourcecfg, pricecfg, doc)
+	assert.Error(t, err)
+}
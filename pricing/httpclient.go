@@ -0,0 +1,180 @@
+package pricing
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+
+	"code.vegaprotocol.io/priceproxy/config"
+	log "github.com/sirupsen/logrus"
+	"go.opentelemetry.io/otel/attribute"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	defaultHTTPTimeout     = 30 * time.Second
+	defaultMaxBodySize     = 10 << 20
+	maxDrainedErrorBodyLen = 4096
+)
+
+// sourceClient makes the HTTP requests of one source, as set up in its config.HTTPClientConfig.
+type sourceClient struct {
+	client      *http.Client
+	header      http.Header
+	maxBodySize int64
+}
+
+func newSourceClient(sourcecfg config.SourceConfig) (*sourceClient, error) {
+	httpcfg := config.HTTPClientConfig{}
+	if sourcecfg.HTTP != nil {
+		httpcfg = *sourcecfg.HTTP
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if httpcfg.Proxy != "" {
+		proxyURL, err := url.Parse(httpcfg.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy for source %s: %w", sourcecfg.Name, err)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	if httpcfg.CABundle != "" {
+		pem, err := os.ReadFile(httpcfg.CABundle)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA bundle for source %s: %w", sourcecfg.Name, err)
+		}
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("invalid CA bundle for source %s: no certificates found in %s", sourcecfg.Name, httpcfg.CABundle)
+		}
+		transport.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	}
+
+	timeout := defaultHTTPTimeout
+	if httpcfg.Timeout > 0 {
+		timeout = time.Duration(httpcfg.Timeout) * time.Second
+	}
+
+	header := http.Header{}
+	for name, value := range httpcfg.Headers {
+		header.Set(name, value)
+	}
+	for name, envName := range httpcfg.HeadersFromEnv {
+		value := os.Getenv(envName)
+		if value == "" {
+			log.WithFields(log.Fields{
+				"sourceName": sourcecfg.Name,
+				"header":     name,
+				"envName":    envName,
+			}).Warnf("The header value is empty. Export the environment variable set in `headers_from_env`")
+			continue
+		}
+		header.Set(name, value)
+	}
+	if httpcfg.UserAgent != "" {
+		header.Set("User-Agent", httpcfg.UserAgent)
+	}
+
+	maxBodySize := int64(defaultMaxBodySize)
+	if httpcfg.MaxBodySize > 0 {
+		maxBodySize = httpcfg.MaxBodySize
+	}
+
+	return &sourceClient{
+		client: &http.Client{
+			Transport: transport,
+			Timeout:   timeout,
+		},
+		header:      header,
+		maxBodySize: maxBodySize,
+	}, nil
+}
+
+// fetchJSON gets url and decodes the JSON response into v. Numbers decoded into interface{} values are
+// kept as json.Number. All errors are returned as *FetchError. The request and the decoding get their own
+// child spans of ctx.
+func (c *sourceClient) fetchJSON(ctx context.Context, url string, v interface{}) error {
+	body, err := c.get(ctx, url)
+	if err != nil {
+		return err
+	}
+
+	_, span := tracer.Start(ctx, "decode", trace.WithAttributes(attribute.Int("size", len(body))))
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	if err = decoder.Decode(v); err != nil {
+		err = &FetchError{Kind: FetchErrorParse, Err: fmt.Errorf("failed to parse data, %w", err)}
+	}
+	endSpan(span, err)
+
+	return err
+}
+
+// get gets url and returns the response body. The query string is left out of the span, as it may hold API keys.
+func (c *sourceClient) get(ctx context.Context, url string) (body []byte, err error) {
+	ctx, span := tracer.Start(ctx, "HTTP GET", trace.WithSpanKind(trace.SpanKindClient), trace.WithAttributes(
+		semconv.HTTPMethod(http.MethodGet),
+		semconv.HTTPURL(stripQuery(url)),
+	))
+	defer func() { endSpan(span, err) }()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, &FetchError{Kind: FetchErrorNetwork, Err: fmt.Errorf("failed to create request, %w", err)}
+	}
+	for name, values := range c.header {
+		req.Header[name] = values
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return nil, &FetchError{Kind: FetchErrorNetwork, Err: fmt.Errorf("failed to get data, %w", err)}
+	}
+	defer resp.Body.Close()
+	span.SetAttributes(semconv.HTTPStatusCode(resp.StatusCode))
+
+	if resp.StatusCode != http.StatusOK {
+		// drain a little of the body, so that the connection can be reused
+		_, _ = io.CopyN(io.Discard, resp.Body, maxDrainedErrorBodyLen)
+		return nil, newStatusError(resp)
+	}
+
+	if resp.ContentLength > c.maxBodySize {
+		return nil, &FetchError{Kind: FetchErrorParse, Err: fmt.Errorf("response too large: %d bytes, max %d", resp.ContentLength, c.maxBodySize)}
+	}
+
+	// read one byte more than allowed, to tell a body of exactly maxBodySize bytes from a bigger one
+	body, err = io.ReadAll(io.LimitReader(resp.Body, c.maxBodySize+1))
+	if err != nil {
+		return nil, &FetchError{Kind: FetchErrorNetwork, Err: fmt.Errorf("failed to read data, %w", err)}
+	}
+	if int64(len(body)) > c.maxBodySize {
+		return nil, &FetchError{Kind: FetchErrorParse, Err: fmt.Errorf("response too large: more than %d bytes", c.maxBodySize)}
+	}
+
+	return body, nil
+}
+
+func stripQuery(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	u.RawQuery = ""
+	u.User = nil
+	return u.String()
+}
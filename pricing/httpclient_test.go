@@ -0,0 +1,74 @@
+package pricing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"code.vegaprotocol.io/priceproxy/config"
+)
+
+func TestSourceClientRequests(t *testing.T) {
+	t.Setenv("PRICEPROXY_TEST_API_KEY", "secret")
+
+	var received http.Header
+	mux := http.NewServeMux()
+	mux.HandleFunc("/price", func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+		_, _ = w.Write([]byte(`{"price": "1.5"}`))
+	})
+	mux.HandleFunc("/big", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"price": "` + strings.Repeat("1", 100) + `"}`))
+	})
+	mux.HandleFunc("/slow", func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client, err := newSourceClient(config.SourceConfig{
+		Name: "test",
+		HTTP: &config.HTTPClientConfig{
+			Timeout:        1,
+			Headers:        map[string]string{"Accept": "application/json"},
+			HeadersFromEnv: map[string]string{"X-Api-Key": "PRICEPROXY_TEST_API_KEY"},
+			UserAgent:      "Mozilla/5.0 (priceproxy)",
+			MaxBodySize:    64,
+		},
+	})
+	require.NoError(t, err)
+
+	var doc interface{}
+	require.NoError(t, client.fetchJSON(context.Background(), server.URL+"/price", &doc))
+	assert.Equal(t, "application/json", received.Get("Accept"))
+	assert.Equal(t, "secret", received.Get("X-Api-Key"))
+	assert.Equal(t, "Mozilla/5.0 (priceproxy)", received.Get("User-Agent"))
+
+	var fetchErr *FetchError
+	err = client.fetchJSON(context.Background(), server.URL+"/big", &doc)
+	require.True(t, errors.As(err, &fetchErr))
+	assert.Equal(t, FetchErrorParse, fetchErr.Kind)
+	assert.Contains(t, err.Error(), "too large")
+
+	err = client.fetchJSON(context.Background(), server.URL+"/slow", &doc)
+	require.True(t, errors.As(err, &fetchErr))
+	assert.Equal(t, FetchErrorNetwork, fetchErr.Kind)
+}
+
+func TestNewSourceClientInvalidCABundle(t *testing.T) {
+	_, err := newSourceClient(config.SourceConfig{
+		Name: "test",
+		HTTP: &config.HTTPClientConfig{CABundle: "/nonexistent/ca.pem"},
+	})
+	assert.Error(t, err)
+}
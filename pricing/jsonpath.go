@@ -0,0 +1,79 @@
+package pricing
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/shopspring/decimal"
+)
+
+// jsonPathLookup walks a decoded JSON document and returns the value found at path.
+// The path is a dot-separated list of object keys and array indexes, optionally starting with "$",
+// e.g. "$.data[0].quote.USD.price", "data.0.quote.USD.price" or "rates.EUR".
+func jsonPathLookup(doc interface{}, path string) (interface{}, error) {
+	current := doc
+	for _, key := range jsonPathKeys(path) {
+		switch node := current.(type) {
+		case map[string]interface{}:
+			value, found := node[key]
+			if !found {
+				// fall back to a case-insensitive match, upstream APIs are inconsistent about key case
+				for k, v := range node {
+					if strings.EqualFold(k, key) {
+						value, found = v, true
+						break
+					}
+				}
+			}
+			if !found {
+				return nil, fmt.Errorf("key not found: %s in %s", key, path)
+			}
+			current = value
+		case []interface{}:
+			idx, err := strconv.Atoi(key)
+			if err != nil {
+				return nil, fmt.Errorf("invalid array index: %s in %s", key, path)
+			}
+			if idx < 0 || idx >= len(node) {
+				return nil, fmt.Errorf("array index out of range: %d in %s", idx, path)
+			}
+			current = node[idx]
+		default:
+			return nil, fmt.Errorf("cannot look up %s in a scalar value in %s", key, path)
+		}
+	}
+
+	return current, nil
+}
+
+func jsonPathKeys(path string) []string {
+	path = strings.TrimPrefix(path, "$")
+	path = strings.ReplaceAll(path, "[", ".")
+	path = strings.ReplaceAll(path, "]", "")
+
+	keys := []string{}
+	for _, key := range strings.Split(path, ".") {
+		if key != "" {
+			keys = append(keys, key)
+		}
+	}
+
+	return keys
+}
+
+// jsonDecimal converts a JSON number, or a string holding a number, to a decimal without losing precision.
+// Documents should be decoded with UseNumber, float64 values are accepted but may already be rounded.
+func jsonDecimal(value interface{}) (decimal.Decimal, error) {
+	switch v := value.(type) {
+	case json.Number:
+		return decimal.NewFromString(v.String())
+	case float64:
+		return decimal.NewFromFloat(v), nil
+	case string:
+		return decimal.NewFromString(strings.TrimSpace(v))
+	default:
+		return decimal.Zero, fmt.Errorf("value is not a number: %v", value)
+	}
+}
@@ -0,0 +1,192 @@
+package pricing
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"code.vegaprotocol.io/priceproxy/config"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/shopspring/decimal"
+)
+
+const metricsNamespace = "priceproxy"
+
+// fetchErrorOther is the error kind reported in metrics for errors which are not a *FetchError.
+const fetchErrorOther FetchErrorKind = "other"
+
+var (
+	sourceSinceSuccessDesc = prometheus.NewDesc(
+		metricsNamespace+"_source_seconds_since_last_success",
+		"Seconds since the last successful fetch from the source. Not reported until the first one.",
+		[]string{"source"}, nil,
+	)
+	priceDesc = prometheus.NewDesc(
+		metricsNamespace+"_price",
+		"Current published price, with its factor applied.",
+		[]string{"source", "base", "quote", "wander"}, nil,
+	)
+	priceAgeDesc = prometheus.NewDesc(
+		metricsNamespace+"_price_age_seconds",
+		"Seconds since the price was last fetched for real. Not reported for prices never fetched.",
+		[]string{"source", "base", "quote", "wander"}, nil,
+	)
+	priceStaleDesc = prometheus.NewDesc(
+		metricsNamespace+"_price_stale",
+		"1 if the price is stale (older than its max age, or never fetched), 0 otherwise.",
+		[]string{"source", "base", "quote", "wander"}, nil,
+	)
+)
+
+// engineMetrics holds the counters and histograms updated by the engine. Gauges are computed when collected,
+// from the state of the engine.
+type engineMetrics struct {
+	fetches       *prometheus.CounterVec
+	fetchErrors   *prometheus.CounterVec
+	fetchDuration *prometheus.HistogramVec
+	priceUpdates  *prometheus.CounterVec
+}
+
+func newEngineMetrics() *engineMetrics {
+	return &engineMetrics{
+		fetches: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "fetches_total",
+			Help:      "Fetches from the source, successful or not.",
+		}, []string{"source"}),
+		fetchErrors: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "fetch_errors_total",
+			Help:      "Failed fetches from the source, and failed requests of fetches making one per price, by kind: network, 4xx, 429, 5xx, parse or other.",
+		}, []string{"source", "kind"}),
+		fetchDuration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: metricsNamespace,
+			Name:      "fetch_duration_seconds",
+			Help:      "Time taken by fetches from the source, successful or not.",
+			Buckets:   []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30},
+		}, []string{"source"}),
+		priceUpdates: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: metricsNamespace,
+			Name:      "price_updates_total",
+			Help:      "Updates of the price, by type: real (fetched or aggregated), wander or scenario.",
+		}, []string{"source", "base", "quote", "wander", "type"}),
+	}
+}
+
+// priceLabels gives the label values of a price, as published (with overrides).
+func priceLabels(pricecfg config.PriceConfig) []string {
+	base, quote := pricecfg.Base, pricecfg.Quote
+	if pricecfg.BaseOverride != "" {
+		base = pricecfg.BaseOverride
+	}
+	if pricecfg.QuoteOverride != "" {
+		quote = pricecfg.QuoteOverride
+	}
+	return []string{pricecfg.Source, base, quote, strconv.FormatBool(pricecfg.Wander)}
+}
+
+func (m *engineMetrics) fetched(source string, duration time.Duration, result *FetchResult, err error) {
+	m.fetches.WithLabelValues(source).Inc()
+	m.fetchDuration.WithLabelValues(source).Observe(duration.Seconds())
+	if err != nil {
+		m.fetchFailed(source, err)
+	}
+	if result != nil {
+		for _, err := range result.Errors {
+			m.fetchFailed(source, err)
+		}
+	}
+}
+
+func (m *engineMetrics) fetchFailed(source string, err error) {
+	kind := fetchErrorOther
+	var fetchErr *FetchError
+	if errors.As(err, &fetchErr) {
+		kind = fetchErr.Kind
+	}
+	m.fetchErrors.WithLabelValues(source, string(kind)).Inc()
+}
+
+func (m *engineMetrics) priceUpdated(pricecfg config.PriceConfig, kind updateKind) {
+	updateType := "real"
+	switch kind {
+	case updateWander:
+		updateType = "wander"
+	case updateScenario:
+		updateType = "scenario"
+	}
+	m.priceUpdates.WithLabelValues(append(priceLabels(pricecfg), updateType)...).Inc()
+}
+
+// forgetSource drops the metrics of a removed source.
+func (m *engineMetrics) forgetSource(source string) {
+	labels := prometheus.Labels{"source": source}
+	m.fetches.DeletePartialMatch(labels)
+	m.fetchErrors.DeletePartialMatch(labels)
+	m.fetchDuration.DeletePartialMatch(labels)
+}
+
+// forgetPrice drops the metrics of a removed price.
+func (m *engineMetrics) forgetPrice(pricecfg config.PriceConfig) {
+	labels := priceLabels(pricecfg)
+	m.priceUpdates.DeletePartialMatch(prometheus.Labels{
+		"source": labels[0],
+		"base":   labels[1],
+		"quote":  labels[2],
+		"wander": labels[3],
+	})
+}
+
+// Describe implements prometheus.Collector.
+func (e *engine) Describe(ch chan<- *prometheus.Desc) {
+	e.metrics.fetches.Describe(ch)
+	e.metrics.fetchErrors.Describe(ch)
+	e.metrics.fetchDuration.Describe(ch)
+	e.metrics.priceUpdates.Describe(ch)
+	ch <- sourceSinceSuccessDesc
+	ch <- priceDesc
+	ch <- priceAgeDesc
+	ch <- priceStaleDesc
+}
+
+// Collect implements prometheus.Collector.
+func (e *engine) Collect(ch chan<- prometheus.Metric) {
+	e.metrics.fetches.Collect(ch)
+	e.metrics.fetchErrors.Collect(ch)
+	e.metrics.fetchDuration.Collect(ch)
+	e.metrics.priceUpdates.Collect(ch)
+
+	now := time.Now()
+
+	e.ratesMu.RLock()
+	for source, health := range e.sourceHealth {
+		if !health.LastSuccess.IsZero() {
+			ch <- prometheus.MustNewConstMetric(sourceSinceSuccessDesc, prometheus.GaugeValue, now.Sub(health.LastSuccess).Seconds(), source)
+		}
+	}
+	e.ratesMu.RUnlock()
+
+	// prices only differing by factor or aggregation have the same labels, only the first one is reported
+	seen := map[[4]string]bool{}
+	for pricecfg, pi := range e.GetPrices() {
+		labels := priceLabels(pricecfg)
+		key := [4]string{labels[0], labels[1], labels[2], labels[3]}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		price := pi.Price.Mul(decimal.NewFromFloat(pricecfg.Factor)).InexactFloat64()
+		ch <- prometheus.MustNewConstMetric(priceDesc, prometheus.GaugeValue, price, labels...)
+
+		freshness := GetFreshness(pi, e.PriceMaxAge(pricecfg), now)
+		if freshness.Status != PriceStatusNeverFetched {
+			ch <- prometheus.MustNewConstMetric(priceAgeDesc, prometheus.GaugeValue, freshness.Age.Seconds(), labels...)
+		}
+		stale := 0.0
+		if freshness.Stale {
+			stale = 1
+		}
+		ch <- prometheus.MustNewConstMetric(priceStaleDesc, prometheus.GaugeValue, stale, labels...)
+	}
+}
@@ -0,0 +1,55 @@
+package pricing
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"code.vegaprotocol.io/priceproxy/config"
+)
+
+func TestMetrics(t *testing.T) {
+	price := config.PriceConfig{Source: "a", Base: "BTC", Quote: "USD", QuoteOverride: "USDT", Factor: 2}
+	e := NewEngine(config.PriceList{price}).(*engine)
+
+	e.recordFetch("a", time.Second, newFetchResult(), nil)
+	e.recordFetch("a", time.Second, nil, &FetchError{Kind: FetchErrorRateLimited, Err: errors.New("slow down")})
+	e.recordFetch("a", time.Second, nil, errors.New("bad config"))
+	assert.False(t, e.sourceHealth["a"].LastSuccess.IsZero())
+
+	// a fetch of one price per request fails if all requests fail
+	failed := newFetchResult()
+	failed.Errors = append(failed.Errors, &FetchError{Kind: FetchErrorNetwork, Err: errors.New("timeout")})
+	e.recordFetch("b", time.Second, failed, nil)
+	assert.True(t, e.sourceHealth["b"].LastSuccess.IsZero())
+	assert.Equal(t, 1.0, testutil.ToFloat64(e.metrics.fetchErrors.WithLabelValues("b", string(FetchErrorNetwork))))
+
+	e.UpdatePrice(price, PriceInfo{Price: decimal.NewFromInt(100), LastUpdatedReal: time.Now()})
+
+	assert.Equal(t, 3.0, testutil.ToFloat64(e.metrics.fetches.WithLabelValues("a")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(e.metrics.fetchErrors.WithLabelValues("a", string(FetchErrorRateLimited))))
+	assert.Equal(t, 1.0, testutil.ToFloat64(e.metrics.fetchErrors.WithLabelValues("a", "other")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(e.metrics.priceUpdates.WithLabelValues("a", "BTC", "USDT", "false", "real")))
+
+	err := testutil.CollectAndCompare(e, strings.NewReader(`
+# HELP priceproxy_price Current published price, with its factor applied.
+# TYPE priceproxy_price gauge
+priceproxy_price{base="BTC",quote="USDT",source="a",wander="false"} 200
+# HELP priceproxy_price_stale 1 if the price is stale (older than its max age, or never fetched), 0 otherwise.
+# TYPE priceproxy_price_stale gauge
+priceproxy_price_stale{base="BTC",quote="USDT",source="a",wander="false"} 0
+`), "priceproxy_price", "priceproxy_price_stale")
+	require.NoError(t, err)
+
+	e.metrics.forgetSource("a")
+	e.metrics.forgetSource("b")
+	e.metrics.forgetPrice(price)
+	assert.Equal(t, 0, testutil.CollectAndCount(e.metrics.fetches))
+	assert.Equal(t, 0, testutil.CollectAndCount(e.metrics.priceUpdates))
+}
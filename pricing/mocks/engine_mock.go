@@ -5,64 +5,107 @@
 package mocks
 
 import (
+	context "context"
+	reflect "reflect"
+	time "time"
+
 	config "code.vegaprotocol.io/priceproxy/config"
 	pricing "code.vegaprotocol.io/priceproxy/pricing"
 	gomock "github.com/golang/mock/gomock"
-	reflect "reflect"
+	prometheus "github.com/prometheus/client_golang/prometheus"
 )
 
-// MockEngine is a mock of Engine interface
+// MockEngine is a mock of Engine interface.
 type MockEngine struct {
 	ctrl     *gomock.Controller
 	recorder *MockEngineMockRecorder
 }
 
-// MockEngineMockRecorder is the mock recorder for MockEngine
+// MockEngineMockRecorder is the mock recorder for MockEngine.
 type MockEngineMockRecorder struct {
 	mock *MockEngine
 }
 
-// NewMockEngine creates a new mock instance
+// NewMockEngine creates a new mock instance.
 func NewMockEngine(ctrl *gomock.Controller) *MockEngine {
 	mock := &MockEngine{ctrl: ctrl}
 	mock.recorder = &MockEngineMockRecorder{mock}
 	return mock
 }
 
-// EXPECT returns an object that allows the caller to indicate expected use
+// EXPECT returns an object that allows the caller to indicate expected use.
 func (m *MockEngine) EXPECT() *MockEngineMockRecorder {
 	return m.recorder
 }
 
-// AddPrice mocks base method
-func (m *MockEngine) AddPrice(arg0 config.PriceConfig) error {
+// AddSource mocks base method.
+func (m *MockEngine) AddSource(arg0 config.SourceConfig) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddPrice", arg0)
+	ret := m.ctrl.Call(m, "AddSource", arg0)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
-// AddPrice indicates an expected call of AddPrice
-func (mr *MockEngineMockRecorder) AddPrice(arg0 interface{}) *gomock.Call {
+// AddSource indicates an expected call of AddSource.
+func (mr *MockEngineMockRecorder) AddSource(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddPrice", reflect.TypeOf((*MockEngine)(nil).AddPrice), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddSource", reflect.TypeOf((*MockEngine)(nil).AddSource), arg0)
 }
 
-// AddSource mocks base method
-func (m *MockEngine) AddSource(arg0 config.SourceConfig) error {
+// CandleIntervals mocks base method.
+func (m *MockEngine) CandleIntervals() []time.Duration {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "AddSource", arg0)
-	ret0, _ := ret[0].(error)
+	ret := m.ctrl.Call(m, "CandleIntervals")
+	ret0, _ := ret[0].([]time.Duration)
 	return ret0
 }
 
-// AddSource indicates an expected call of AddSource
-func (mr *MockEngineMockRecorder) AddSource(arg0 interface{}) *gomock.Call {
+// CandleIntervals indicates an expected call of CandleIntervals.
+func (mr *MockEngineMockRecorder) CandleIntervals() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddSource", reflect.TypeOf((*MockEngine)(nil).AddSource), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CandleIntervals", reflect.TypeOf((*MockEngine)(nil).CandleIntervals))
+}
+
+// Collect mocks base method.
+func (m *MockEngine) Collect(arg0 chan<- prometheus.Metric) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Collect", arg0)
 }
 
-// GetPrice mocks base method
+// Collect indicates an expected call of Collect.
+func (mr *MockEngineMockRecorder) Collect(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Collect", reflect.TypeOf((*MockEngine)(nil).Collect), arg0)
+}
+
+// Describe mocks base method.
+func (m *MockEngine) Describe(arg0 chan<- *prometheus.Desc) {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Describe", arg0)
+}
+
+// Describe indicates an expected call of Describe.
+func (mr *MockEngineMockRecorder) Describe(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Describe", reflect.TypeOf((*MockEngine)(nil).Describe), arg0)
+}
+
+// GetCandles mocks base method.
+func (m *MockEngine) GetCandles(arg0 config.PriceConfig, arg1 time.Duration, arg2, arg3 time.Time) ([]pricing.Candle, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetCandles", arg0, arg1, arg2, arg3)
+	ret0, _ := ret[0].([]pricing.Candle)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetCandles indicates an expected call of GetCandles.
+func (mr *MockEngineMockRecorder) GetCandles(arg0, arg1, arg2, arg3 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetCandles", reflect.TypeOf((*MockEngine)(nil).GetCandles), arg0, arg1, arg2, arg3)
+}
+
+// GetPrice mocks base method.
 func (m *MockEngine) GetPrice(arg0 config.PriceConfig) (pricing.PriceInfo, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetPrice", arg0)
@@ -71,13 +114,28 @@ func (m *MockEngine) GetPrice(arg0 config.PriceConfig) (pricing.PriceInfo, error
 	return ret0, ret1
 }
 
-// GetPrice indicates an expected call of GetPrice
+// GetPrice indicates an expected call of GetPrice.
 func (mr *MockEngineMockRecorder) GetPrice(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPrice", reflect.TypeOf((*MockEngine)(nil).GetPrice), arg0)
 }
 
-// GetPrices mocks base method
+// GetPriceHistory mocks base method.
+func (m *MockEngine) GetPriceHistory(arg0 config.PriceConfig, arg1, arg2 time.Time) ([]pricing.PricePoint, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetPriceHistory", arg0, arg1, arg2)
+	ret0, _ := ret[0].([]pricing.PricePoint)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetPriceHistory indicates an expected call of GetPriceHistory.
+func (mr *MockEngineMockRecorder) GetPriceHistory(arg0, arg1, arg2 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPriceHistory", reflect.TypeOf((*MockEngine)(nil).GetPriceHistory), arg0, arg1, arg2)
+}
+
+// GetPrices mocks base method.
 func (m *MockEngine) GetPrices() map[config.PriceConfig]pricing.PriceInfo {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetPrices")
@@ -85,13 +143,41 @@ func (m *MockEngine) GetPrices() map[config.PriceConfig]pricing.PriceInfo {
 	return ret0
 }
 
-// GetPrices indicates an expected call of GetPrices
+// GetPrices indicates an expected call of GetPrices.
 func (mr *MockEngineMockRecorder) GetPrices() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetPrices", reflect.TypeOf((*MockEngine)(nil).GetPrices))
 }
 
-// GetSource mocks base method
+// GetRejections mocks base method.
+func (m *MockEngine) GetRejections(arg0 config.PriceConfig) pricing.Rejections {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetRejections", arg0)
+	ret0, _ := ret[0].(pricing.Rejections)
+	return ret0
+}
+
+// GetRejections indicates an expected call of GetRejections.
+func (mr *MockEngineMockRecorder) GetRejections(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetRejections", reflect.TypeOf((*MockEngine)(nil).GetRejections), arg0)
+}
+
+// GetScenarios mocks base method.
+func (m *MockEngine) GetScenarios() []pricing.ScenarioStatus {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetScenarios")
+	ret0, _ := ret[0].([]pricing.ScenarioStatus)
+	return ret0
+}
+
+// GetScenarios indicates an expected call of GetScenarios.
+func (mr *MockEngineMockRecorder) GetScenarios() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetScenarios", reflect.TypeOf((*MockEngine)(nil).GetScenarios))
+}
+
+// GetSource mocks base method.
 func (m *MockEngine) GetSource(arg0 string) (config.SourceConfig, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetSource", arg0)
@@ -100,13 +186,27 @@ func (m *MockEngine) GetSource(arg0 string) (config.SourceConfig, error) {
 	return ret0, ret1
 }
 
-// GetSource indicates an expected call of GetSource
+// GetSource indicates an expected call of GetSource.
 func (mr *MockEngineMockRecorder) GetSource(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSource", reflect.TypeOf((*MockEngine)(nil).GetSource), arg0)
 }
 
-// GetSources mocks base method
+// GetSourceHealth mocks base method.
+func (m *MockEngine) GetSourceHealth() map[string]pricing.SourceHealth {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetSourceHealth")
+	ret0, _ := ret[0].(map[string]pricing.SourceHealth)
+	return ret0
+}
+
+// GetSourceHealth indicates an expected call of GetSourceHealth.
+func (mr *MockEngineMockRecorder) GetSourceHealth() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSourceHealth", reflect.TypeOf((*MockEngine)(nil).GetSourceHealth))
+}
+
+// GetSources mocks base method.
 func (m *MockEngine) GetSources() ([]config.SourceConfig, error) {
 	m.ctrl.T.Helper()
 	ret := m.ctrl.Call(m, "GetSources")
@@ -115,34 +215,202 @@ func (m *MockEngine) GetSources() ([]config.SourceConfig, error) {
 	return ret0, ret1
 }
 
-// GetSources indicates an expected call of GetSources
+// GetSources indicates an expected call of GetSources.
 func (mr *MockEngineMockRecorder) GetSources() *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetSources", reflect.TypeOf((*MockEngine)(nil).GetSources))
 }
 
-// UpdatePrice mocks base method
+// LastUpdateSeq mocks base method.
+func (m *MockEngine) LastUpdateSeq() uint64 {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "LastUpdateSeq")
+	ret0, _ := ret[0].(uint64)
+	return ret0
+}
+
+// LastUpdateSeq indicates an expected call of LastUpdateSeq.
+func (mr *MockEngineMockRecorder) LastUpdateSeq() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "LastUpdateSeq", reflect.TypeOf((*MockEngine)(nil).LastUpdateSeq))
+}
+
+// PauseSource mocks base method.
+func (m *MockEngine) PauseSource(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PauseSource", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// PauseSource indicates an expected call of PauseSource.
+func (mr *MockEngineMockRecorder) PauseSource(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PauseSource", reflect.TypeOf((*MockEngine)(nil).PauseSource), arg0)
+}
+
+// PausedSources mocks base method.
+func (m *MockEngine) PausedSources() []string {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PausedSources")
+	ret0, _ := ret[0].([]string)
+	return ret0
+}
+
+// PausedSources indicates an expected call of PausedSources.
+func (mr *MockEngineMockRecorder) PausedSources() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PausedSources", reflect.TypeOf((*MockEngine)(nil).PausedSources))
+}
+
+// PriceList mocks base method.
+func (m *MockEngine) PriceList(arg0 string) config.PriceList {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PriceList", arg0)
+	ret0, _ := ret[0].(config.PriceList)
+	return ret0
+}
+
+// PriceList indicates an expected call of PriceList.
+func (mr *MockEngineMockRecorder) PriceList(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PriceList", reflect.TypeOf((*MockEngine)(nil).PriceList), arg0)
+}
+
+// PriceMaxAge mocks base method.
+func (m *MockEngine) PriceMaxAge(arg0 config.PriceConfig) time.Duration {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PriceMaxAge", arg0)
+	ret0, _ := ret[0].(time.Duration)
+	return ret0
+}
+
+// PriceMaxAge indicates an expected call of PriceMaxAge.
+func (mr *MockEngineMockRecorder) PriceMaxAge(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PriceMaxAge", reflect.TypeOf((*MockEngine)(nil).PriceMaxAge), arg0)
+}
+
+// Reload mocks base method.
+func (m *MockEngine) Reload(arg0 config.PriceList, arg1 []config.SourceConfig) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Reload", arg0, arg1)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Reload indicates an expected call of Reload.
+func (mr *MockEngineMockRecorder) Reload(arg0, arg1 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Reload", reflect.TypeOf((*MockEngine)(nil).Reload), arg0, arg1)
+}
+
+// ResumeSource mocks base method.
+func (m *MockEngine) ResumeSource(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ResumeSource", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// ResumeSource indicates an expected call of ResumeSource.
+func (mr *MockEngineMockRecorder) ResumeSource(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ResumeSource", reflect.TypeOf((*MockEngine)(nil).ResumeSource), arg0)
+}
+
+// StartFetching mocks base method.
+func (m *MockEngine) StartFetching(arg0 context.Context) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartFetching", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StartFetching indicates an expected call of StartFetching.
+func (mr *MockEngineMockRecorder) StartFetching(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartFetching", reflect.TypeOf((*MockEngine)(nil).StartFetching), arg0)
+}
+
+// StartScenario mocks base method.
+func (m *MockEngine) StartScenario(arg0 config.ScenarioConfig) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StartScenario", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StartScenario indicates an expected call of StartScenario.
+func (mr *MockEngineMockRecorder) StartScenario(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StartScenario", reflect.TypeOf((*MockEngine)(nil).StartScenario), arg0)
+}
+
+// Stop mocks base method.
+func (m *MockEngine) Stop() {
+	m.ctrl.T.Helper()
+	m.ctrl.Call(m, "Stop")
+}
+
+// Stop indicates an expected call of Stop.
+func (mr *MockEngineMockRecorder) Stop() *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Stop", reflect.TypeOf((*MockEngine)(nil).Stop))
+}
+
+// StopScenario mocks base method.
+func (m *MockEngine) StopScenario(arg0 string) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "StopScenario", arg0)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// StopScenario indicates an expected call of StopScenario.
+func (mr *MockEngineMockRecorder) StopScenario(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "StopScenario", reflect.TypeOf((*MockEngine)(nil).StopScenario), arg0)
+}
+
+// Subscribe mocks base method.
+func (m *MockEngine) Subscribe(arg0 int) (<-chan pricing.PriceUpdate, func()) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Subscribe", arg0)
+	ret0, _ := ret[0].(<-chan pricing.PriceUpdate)
+	ret1, _ := ret[1].(func())
+	return ret0, ret1
+}
+
+// Subscribe indicates an expected call of Subscribe.
+func (mr *MockEngineMockRecorder) Subscribe(arg0 interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Subscribe", reflect.TypeOf((*MockEngine)(nil).Subscribe), arg0)
+}
+
+// UpdatePrice mocks base method.
 func (m *MockEngine) UpdatePrice(arg0 config.PriceConfig, arg1 pricing.PriceInfo) {
 	m.ctrl.T.Helper()
 	m.ctrl.Call(m, "UpdatePrice", arg0, arg1)
 }
 
-// UpdatePrice indicates an expected call of UpdatePrice
+// UpdatePrice indicates an expected call of UpdatePrice.
 func (mr *MockEngineMockRecorder) UpdatePrice(arg0, arg1 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatePrice", reflect.TypeOf((*MockEngine)(nil).UpdatePrice), arg0, arg1)
 }
 
-// WaitForPrice mocks base method
-func (m *MockEngine) WaitForPrice(arg0 config.PriceConfig) pricing.PriceInfo {
+// UpdatesSince mocks base method.
+func (m *MockEngine) UpdatesSince(arg0 uint64) ([]pricing.PriceUpdate, bool) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "WaitForPrice", arg0)
-	ret0, _ := ret[0].(pricing.PriceInfo)
-	return ret0
+	ret := m.ctrl.Call(m, "UpdatesSince", arg0)
+	ret0, _ := ret[0].([]pricing.PriceUpdate)
+	ret1, _ := ret[1].(bool)
+	return ret0, ret1
 }
 
-// WaitForPrice indicates an expected call of WaitForPrice
-func (mr *MockEngineMockRecorder) WaitForPrice(arg0 interface{}) *gomock.Call {
+// UpdatesSince indicates an expected call of UpdatesSince.
+func (mr *MockEngineMockRecorder) UpdatesSince(arg0 interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WaitForPrice", reflect.TypeOf((*MockEngine)(nil).WaitForPrice), arg0)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdatesSince", reflect.TypeOf((*MockEngine)(nil).UpdatesSince), arg0)
 }
@@ -1,26 +1,49 @@
 package pricing
 
 import (
+	"context"
 	"fmt"
+	"sort"
 	"sync"
 	"time"
 
 	"code.vegaprotocol.io/priceproxy/config"
+	"code.vegaprotocol.io/priceproxy/expression"
+	"code.vegaprotocol.io/priceproxy/utils"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/shopspring/decimal"
 )
 
+// divisionPrecision is the number of decimal places kept when dividing prices, e.g. when converting them.
+const divisionPrecision = 18
+
 // PriceInfo describes a price from a source.
 // The price may be a real updated from an upstream source, or one that has been wandered.
 // The LastUpdated timstamps indicate when the price was last fetched for real and when (if at all) it was last wandered.
+// Contributions is only set for aggregated prices, and Conversion only for prices converted from other rates.
+// Restored is true for prices loaded from a snapshot at startup, until they are fetched again.
+// Volume is the traded volume returned by the source with the price, zero if it returns none: the volume over the
+// last 24 hours in the base currency for bitstamp, in the quote currency for coinmarketcap, and whatever
+// volume_path points at for HTTP/JSON sources. VWAPs use it as a cumulative volume (see config.DeriveConfig).
+// Scenario is the name of the scenario overriding the price, or one of the prices it is calculated from, if any
+// (see StartScenario).
 type PriceInfo struct {
-	Price             float64
+	Price             decimal.Decimal
 	LastUpdatedReal   time.Time
 	LastUpdatedWander time.Time
+	Contributions     []PriceContribution
+	Conversion        []ConversionStep
+	Restored          bool
+	Volume            decimal.Decimal
+	Scenario          string
 }
 
 // Engine is the source of price information from multiple external/internal/fake sources.
 //
 //go:generate go run github.com/golang/mock/mockgen -destination mocks/engine_mock.go -package mocks code.vegaprotocol.io/priceproxy/pricing Engine
 type Engine interface {
+	prometheus.Collector
+
 	AddSource(sourcecfg config.SourceConfig) error
 	GetSource(name string) (config.SourceConfig, error)
 	GetSources() ([]config.SourceConfig, error)
@@ -29,40 +52,222 @@ type Engine interface {
 	GetPrice(pricecfg config.PriceConfig) (PriceInfo, error)
 	GetPrices() map[config.PriceConfig]PriceInfo
 	UpdatePrice(pricecfg config.PriceConfig, newPrice PriceInfo)
+	GetPriceHistory(pricecfg config.PriceConfig, from, to time.Time) ([]PricePoint, error)
+	GetCandles(pricecfg config.PriceConfig, interval time.Duration, from, to time.Time) ([]Candle, error)
+	CandleIntervals() []time.Duration
+	PriceMaxAge(pricecfg config.PriceConfig) time.Duration
+	GetRejections(pricecfg config.PriceConfig) Rejections
+	GetSourceHealth() map[string]SourceHealth
+	Subscribe(buffer int) (<-chan PriceUpdate, func())
+	UpdatesSince(seq uint64) ([]PriceUpdate, bool)
+	LastUpdateSeq() uint64
+
+	StartScenario(scenariocfg config.ScenarioConfig) error
+	StopScenario(name string) error
+	GetScenarios() []ScenarioStatus
 
-	StartFetching() error
+	StartFetching(ctx context.Context) error
+	Reload(prices config.PriceList, sources []config.SourceConfig) error
+	PauseSource(name string) error
+	ResumeSource(name string) error
+	PausedSources() []string
+	Stop()
 }
 
 type priceBoard interface {
 	PriceList(source string) config.PriceList
 	UpdatePrice(pricecfg config.PriceConfig, newPrice PriceInfo)
+	UpdateRates(source string, rates []Rate)
+	GetRates(source string) []Rate
+	recordFetch(source string, duration time.Duration, result *FetchResult, err error)
 }
 
 type engine struct {
-	priceList config.PriceList
-	prices    map[config.PriceConfig]PriceInfo
-	pricesMu  sync.RWMutex
+	priceList  config.PriceList
+	prices     map[config.PriceKey]PriceInfo
+	realPrices map[config.PriceKey]PriceInfo
+	pricesMu   sync.RWMutex
+
+	// fetchList has the prices fetched from sources: the non-aggregated prices and the components of
+	// aggregated, derived and synthetic prices. Components which are not also published on their own are kept
+	// in componentPrices. aggregates has the prices calculated from each component, and from each price
+	// referenced by synthetic prices. windows has the recent updates derived prices are calculated from, and
+	// expressions the parsed expressions of synthetic prices.
+	fetchList       config.PriceList
+	aggregates      map[config.PriceKey]config.PriceList
+	componentPrices map[config.PriceKey]PriceInfo
+	hidden          map[config.PriceKey]bool
+	windows         map[config.PriceKey]*priceWindow
+	expressions     map[config.PriceKey]*expression.Expression
+
+	validator *priceValidator
+
+	// scenarios has the running scenarios by name, and overrides the scenario overriding each price, published
+	// or component. While a price is overridden, its real updates only go to realPrices. scenarioRuns has the
+	// goroutines moving the prices of the running scenarios.
+	scenarios    map[string]*scenarioRun
+	overrides    map[config.PriceKey]string
+	scenarioRuns sync.WaitGroup
+
+	subscribers subscribers
+
+	// sourceHealth has the outcome of the recent fetches of each source, and is protected by ratesMu.
+	rates        map[string][]Rate
+	sourceHealth map[string]SourceHealth
+	ratesMu      sync.RWMutex
+
+	metrics *engineMetrics
+
+	histories        map[config.PriceKey]*priceHistory
+	historyRetention time.Duration
+	historyMaxPoints int
+
+	candles         map[config.PriceKey]map[time.Duration]*candleSeries
+	candleIntervals []time.Duration
+	maxCandles      int
+
+	snapshotPath     string
+	snapshotInterval time.Duration
 
 	sources   map[string]config.SourceConfig
 	sourcesMu sync.Mutex
+
+	// ctx is the context the fetchers run in, nil when not fetching. runners has the fetchers of each source.
+	ctx       context.Context
+	cancel    context.CancelFunc
+	runners   map[string]*sourceRunner
+	paused    map[string]bool
+	snapshots sync.WaitGroup
+	runningMu sync.Mutex
+}
+
+// EngineOption configures optional features of the pricing engine.
+type EngineOption func(e *engine)
+
+// WithHistory sets how much price history the engine keeps.
+func WithHistory(historycfg config.HistoryConfig) EngineOption {
+	return func(e *engine) {
+		if historycfg.Retention > 0 {
+			e.historyRetention = time.Duration(historycfg.Retention) * time.Second
+		}
+		if historycfg.MaxPoints > 0 {
+			e.historyMaxPoints = historycfg.MaxPoints
+		}
+	}
+}
+
+// WithCandles sets the intervals candles are built at, and how many are kept.
+func WithCandles(candlescfg config.CandlesConfig) EngineOption {
+	return func(e *engine) {
+		if len(candlescfg.Intervals) > 0 {
+			intervals := make([]time.Duration, 0, len(candlescfg.Intervals))
+			for _, interval := range candlescfg.Intervals {
+				intervals = append(intervals, time.Duration(interval)*time.Second)
+			}
+			sort.Slice(intervals, func(i, j int) bool { return intervals[i] < intervals[j] })
+			e.candleIntervals = intervals[:0]
+			for _, interval := range intervals {
+				if len(e.candleIntervals) == 0 || e.candleIntervals[len(e.candleIntervals)-1] != interval {
+					e.candleIntervals = append(e.candleIntervals, interval)
+				}
+			}
+		}
+		if candlescfg.MaxCandles > 0 {
+			e.maxCandles = candlescfg.MaxCandles
+		}
+	}
 }
 
 // NewEngine creates a new pricing engine.
-func NewEngine(prices config.PriceList) Engine {
+func NewEngine(prices config.PriceList, opts ...EngineOption) Engine {
 	e := engine{
-		priceList: prices,
-		pricesMu:  sync.RWMutex{},
-		sourcesMu: sync.Mutex{},
-		prices:    make(map[config.PriceConfig]PriceInfo),
-		sources:   make(map[string]config.SourceConfig),
+		priceList:       prices,
+		pricesMu:        sync.RWMutex{},
+		sourcesMu:       sync.Mutex{},
+		prices:          make(map[config.PriceKey]PriceInfo),
+		realPrices:      make(map[config.PriceKey]PriceInfo),
+		componentPrices: make(map[config.PriceKey]PriceInfo),
+		windows:         make(map[config.PriceKey]*priceWindow),
+		sources:         make(map[string]config.SourceConfig),
+		validator:       newPriceValidator(),
+		scenarios:       make(map[string]*scenarioRun),
+		overrides:       make(map[config.PriceKey]string),
+		subscribers:     newSubscribers(),
+		rates:           make(map[string][]Rate),
+		sourceHealth:    make(map[string]SourceHealth),
+		metrics:         newEngineMetrics(),
+		runners:         make(map[string]*sourceRunner),
+		paused:          make(map[string]bool),
+
+		histories:        make(map[config.PriceKey]*priceHistory),
+		historyRetention: defaultHistoryRetention,
+		historyMaxPoints: defaultHistoryMaxPoints,
+		candles:          make(map[config.PriceKey]map[time.Duration]*candleSeries),
+		candleIntervals:  defaultCandleIntervals,
+		maxCandles:       defaultMaxCandles,
+
+		snapshotInterval: defaultSnapshotInterval,
 	}
+
+	for _, opt := range opts {
+		opt(&e)
+	}
+
+	e.setFetchList(prices)
+
 	return &e
 }
 
-func (e *engine) AddSource(sourcecfg config.SourceConfig) error {
+// setFetchList works out which prices are fetched from sources, see fetchList. It must be called with
+// pricesMu held.
+func (e *engine) setFetchList(prices config.PriceList) {
+	e.priceList = prices
+	e.fetchList = config.PriceList{}
+	e.aggregates = make(map[config.PriceKey]config.PriceList)
+	e.hidden = make(map[config.PriceKey]bool)
+	e.expressions = make(map[config.PriceKey]*expression.Expression)
+
+	for _, price := range prices {
+		if price.Fetched() {
+			e.fetchList = append(e.fetchList, price)
+		}
+	}
+	for _, price := range prices {
+		if price.Synthetic != nil {
+			if expr, err := expression.Parse(price.Synthetic.Expression); err == nil {
+				e.expressions[price.Key()] = expr
+			}
+		}
+		for _, component := range price.Components() {
+			if dependency, found := prices.Resolve(component); found && price.Synthetic != nil {
+				key := dependency.Key()
+				e.aggregates[key] = append(e.aggregates[key], price)
+				continue
+			}
+			key := component.Key()
+			if _, found := e.aggregates[key]; !found && !utils.InSlice(component, e.fetchList) {
+				e.fetchList = append(e.fetchList, component)
+				e.hidden[key] = true
+			}
+			e.aggregates[key] = append(e.aggregates[key], price)
+		}
+	}
+}
+
+func checkSource(sourcecfg config.SourceConfig) error {
 	if sourcecfg.SleepReal == 0 {
 		return fmt.Errorf("invalid source config: sleepReal is zero")
 	}
+	if _, err := fetcherFactory(sourcecfg.SourceType()); err != nil {
+		return fmt.Errorf("invalid source config: %w", err)
+	}
+	return nil
+}
+
+func (e *engine) AddSource(sourcecfg config.SourceConfig) error {
+	if err := checkSource(sourcecfg); err != nil {
+		return err
+	}
 
 	e.sourcesMu.Lock()
 	defer e.sourcesMu.Unlock()
@@ -105,7 +310,7 @@ func (e *engine) GetPrice(pricecfg config.PriceConfig) (PriceInfo, error) {
 	e.pricesMu.RLock()
 	defer e.pricesMu.RUnlock()
 
-	pi, found := e.prices[pricecfg]
+	pi, found := e.prices[pricecfg.Key()]
 	if !found {
 		return PriceInfo{}, fmt.Errorf("price not found: %s", pricecfg.String())
 	}
@@ -117,56 +322,333 @@ func (e *engine) GetPrices() map[config.PriceConfig]PriceInfo {
 	defer e.pricesMu.RUnlock()
 	results := map[config.PriceConfig]PriceInfo{}
 
-	for k, v := range e.prices {
-		results[k] = v
+	for _, price := range e.priceList {
+		if pi, found := e.prices[price.Key()]; found {
+			results[price] = pi
+		}
 	}
 	return results
 }
 
+// UpdatePrice publishes a real price, unless it fails validation (see config.ValidationConfig).
 func (e *engine) UpdatePrice(pricecfg config.PriceConfig, newPrice PriceInfo) {
+	validationcfg := e.validationConfig(pricecfg.Source)
+
 	e.pricesMu.Lock()
-	e.prices[pricecfg] = newPrice
-	e.pricesMu.Unlock()
+	defer e.pricesMu.Unlock()
+
+	if !utils.InSlice(pricecfg, e.fetchList) {
+		// removed by a reload while it was being fetched
+		return
+	}
+
+	key := pricecfg.Key()
+	if !e.validator.validate(pricecfg, validationcfg, newPrice, time.Now()) {
+		return
+	}
+
+	e.realPrices[key] = newPrice
+	if _, overridden := e.overrides[key]; overridden {
+		return
+	}
+
+	if !e.hidden[key] {
+		e.prices[key] = newPrice
+		e.recordHistory(pricecfg, newPrice, updateReal)
+		e.metrics.priceUpdated(pricecfg, updateReal)
+		e.publish(pricecfg, newPrice)
+	}
+
+	if aggregated, found := e.aggregates[key]; found {
+		e.componentPrices[key] = newPrice
+		e.updateAggregates(aggregated)
+	}
+}
+
+// UpdateRates keeps the rates from the latest fetch of a source, for other sources to convert prices with.
+func (e *engine) UpdateRates(source string, rates []Rate) {
+	e.ratesMu.Lock()
+	defer e.ratesMu.Unlock()
+
+	e.rates[source] = rates
+}
+
+// GetRates returns the rates from the latest fetch of a source.
+func (e *engine) GetRates(source string) []Rate {
+	e.ratesMu.RLock()
+	defer e.ratesMu.RUnlock()
+
+	return e.rates[source]
+}
+
+func (e *engine) validationConfig(source string) *config.ValidationConfig {
+	e.sourcesMu.Lock()
+	defer e.sourcesMu.Unlock()
+
+	return e.sources[source].Validation
+}
+
+// GetRejections returns how many fetched prices were rejected for a price, by reason.
+func (e *engine) GetRejections(pricecfg config.PriceConfig) Rejections {
+	e.pricesMu.RLock()
+	defer e.pricesMu.RUnlock()
+
+	return e.validator.getRejections(pricecfg)
+}
+
+// updateAggregates recalculates aggregated, derived and synthetic prices, and then the synthetic prices
+// referencing them. It must be called with pricesMu held.
+func (e *engine) updateAggregates(aggregated config.PriceList) {
+	now := time.Now().Round(0)
+	for _, price := range aggregated {
+		key := price.Key()
+		var (
+			newPrice PriceInfo
+			ok       bool
+		)
+		switch {
+		case price.Derive != nil:
+			newPrice, ok = e.derive(price, now)
+		case price.Synthetic != nil:
+			newPrice, ok = e.synthesize(price, now)
+		default:
+			newPrice, ok = aggregate(price, e.prices[key], e.componentPrices, now)
+		}
+		if ok {
+			e.realPrices[key] = newPrice
+		}
+		if _, overridden := e.overrides[key]; !overridden {
+			e.prices[key] = newPrice
+			if ok {
+				kind := updateReal
+				if newPrice.Scenario != "" {
+					kind = updateScenario
+				}
+				e.recordHistory(price, newPrice, kind)
+				e.metrics.priceUpdated(price, kind)
+			}
+			e.publish(price, newPrice)
+		}
+
+		if dependents, found := e.aggregates[key]; found && ok {
+			e.updateAggregates(dependents)
+		}
+	}
+}
+
+// recordHistory adds a point to the history and the candles of a price. It must be called with pricesMu held.
+func (e *engine) recordHistory(pricecfg config.PriceConfig, pi PriceInfo, kind updateKind) {
+	key := pricecfg.Key()
+	history, found := e.histories[key]
+	if !found {
+		history = newPriceHistory(e.historyMaxPoints, e.historyRetention)
+		e.histories[key] = history
+	}
+
+	point := PricePoint{
+		Price:           pi.Price,
+		LastUpdatedReal: pi.LastUpdatedReal,
+		Time:            time.Now().Round(0),
+		Wandered:        kind == updateWander,
+		Scenario:        kind == updateScenario,
+	}
+	history.add(point)
+	e.recordCandles(pricecfg, point)
+}
+
+// GetPriceHistory returns the recorded points of a price between from and to (both inclusive, zero for no limit),
+// oldest first.
+func (e *engine) GetPriceHistory(pricecfg config.PriceConfig, from, to time.Time) ([]PricePoint, error) {
+	e.pricesMu.RLock()
+	defer e.pricesMu.RUnlock()
+
+	key := pricecfg.Key()
+	if _, found := e.prices[key]; !found {
+		return nil, fmt.Errorf("price not found: %s", pricecfg.String())
+	}
+
+	history, found := e.histories[key]
+	if !found {
+		return []PricePoint{}, nil
+	}
+	return history.between(from, to, time.Now()), nil
 }
 
+// wanderPrice replaces the current price with the one returned by wander, which is given the current
+// (possibly already wandered) price and the last real price. The real price is left untouched, so the
+// next real update snaps the price back.
+func (e *engine) wanderPrice(pricecfg config.PriceConfig, wander func(current, real PriceInfo) (PriceInfo, bool)) {
+	e.pricesMu.Lock()
+	defer e.pricesMu.Unlock()
+
+	key := pricecfg.Key()
+	current, found := e.prices[key]
+	if !found {
+		return
+	}
+	if _, overridden := e.overrides[key]; overridden {
+		return
+	}
+	real, found := e.realPrices[key]
+	if !found {
+		return
+	}
+
+	if newPrice, ok := wander(current, real); ok {
+		e.prices[key] = newPrice
+		e.recordHistory(pricecfg, newPrice, updateWander)
+		e.metrics.priceUpdated(pricecfg, updateWander)
+		e.publish(pricecfg, newPrice)
+	}
+}
+
+// PriceList returns the prices fetched from a source, including the ones only used for aggregated prices.
 func (e *engine) PriceList(source string) config.PriceList {
-	return e.priceList.GetBySource(source)
+	e.pricesMu.RLock()
+	defer e.pricesMu.RUnlock()
+
+	return e.fetchList.GetBySource(source)
 }
 
 func (e *engine) initPrices() {
+	e.pricesMu.Lock()
+	defer e.pricesMu.Unlock()
+
 	for _, price := range e.priceList {
-		e.UpdatePrice(price, PriceInfo{
-			Price:             0.0,
+		e.prices[price.Key()] = PriceInfo{
+			Price:             decimal.Zero,
 			LastUpdatedReal:   time.Unix(0, 0),
 			LastUpdatedWander: time.Now(),
-		})
+		}
+	}
+
+	if e.snapshotPath != "" {
+		e.restoreSnapshot()
 	}
 }
 
-func (e *engine) StartFetching() error {
+// StartFetching starts one fetcher (and, if configured, one wanderer) per source, and the snapshot writer if
+// configured. They run until ctx is cancelled or Stop is called.
+func (e *engine) StartFetching(ctx context.Context) error {
+	e.runningMu.Lock()
+	defer e.runningMu.Unlock()
+
+	if e.cancel != nil {
+		return fmt.Errorf("already fetching")
+	}
+
 	e.initPrices()
 
-	for _, sourceConfig := range e.sources {
-		if sourceConfig.IsCoinGecko() {
-			go coingeckoStartFetching(e, sourceConfig)
-			continue
+	e.sourcesMu.Lock()
+	sources := make([]config.SourceConfig, 0, len(e.sources))
+	for _, sourcecfg := range e.sources {
+		sources = append(sources, sourcecfg)
+	}
+	e.sourcesMu.Unlock()
+
+	fetchers, err := newFetchers(sources)
+	if err != nil {
+		return err
+	}
+
+	e.ctx, e.cancel = context.WithCancel(ctx)
+
+	for _, sourcecfg := range sources {
+		if !e.paused[sourcecfg.Name] {
+			e.runners[sourcecfg.Name] = e.startSource(sourcecfg, fetchers[sourcecfg.Name])
 		}
-		if sourceConfig.IsCoinMarketCap() {
-			go coinmarketcapStartFetching(e, sourceConfig)
-			continue
+	}
+
+	if e.snapshotPath != "" {
+		e.snapshots.Add(1)
+		go func() {
+			defer e.snapshots.Done()
+			e.snapshotStart(e.ctx)
+		}()
+	}
+
+	return nil
+}
+
+func newFetchers(sources []config.SourceConfig) (map[string]Fetcher, error) {
+	fetchers := make(map[string]Fetcher, len(sources))
+	for _, sourcecfg := range sources {
+		fetcher, err := newFetcher(sourcecfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create fetcher for source %s: %w", sourcecfg.Name, err)
 		}
-		if sourceConfig.IsBitstamp() {
-			go bitstampStartFetching(e, sourceConfig)
-			continue
+		fetchers[sourcecfg.Name] = fetcher
+	}
+	return fetchers, nil
+}
+
+// sourceRunner holds the fetcher and wanderer goroutines of one source.
+type sourceRunner struct {
+	cancel context.CancelFunc
+	done   sync.WaitGroup
+}
+
+// startSource starts the fetcher (and, if configured, the wanderer) of one source. It must be called with
+// runningMu held, while fetching.
+func (e *engine) startSource(sourcecfg config.SourceConfig, fetcher Fetcher) *sourceRunner {
+	runner := &sourceRunner{}
+	var ctx context.Context
+	ctx, runner.cancel = context.WithCancel(e.ctx)
+
+	runner.done.Add(1)
+	go func() {
+		defer runner.done.Done()
+		startFetching(ctx, e, sourcecfg, fetcher)
+	}()
+
+	if sourcecfg.SleepWander > 0 {
+		runner.done.Add(1)
+		go func() {
+			defer runner.done.Done()
+			wanderStart(ctx, e, sourcecfg)
+		}()
+	}
+
+	return runner
+}
+
+// stop cancels the goroutines of the source, including in-flight upstream requests, and waits for them to exit.
+func (r *sourceRunner) stop() {
+	r.cancel()
+	r.done.Wait()
+}
+
+// Stop cancels all fetchers and wanderers, including in-flight upstream requests, and waits for them to exit.
+// Running scenarios are stopped first, and their prices returned to their real price. When snapshots are
+// configured, a last snapshot is saved before Stop returns.
+func (e *engine) Stop() {
+	e.runningMu.Lock()
+	defer e.runningMu.Unlock()
+
+	e.pricesMu.Lock()
+	for _, run := range e.scenarios {
+		for _, timeline := range run.timelines {
+			e.endOverride(timeline.price)
 		}
+		e.endScenario(run)
+	}
+	e.pricesMu.Unlock()
+	e.scenarioRuns.Wait()
 
-		go httpStartFetching(e, sourceConfig)
+	if e.cancel == nil {
+		return
 	}
 
-	return nil
+	e.cancel()
+	for name, runner := range e.runners {
+		runner.stop()
+		delete(e.runners, name)
+	}
+	e.snapshots.Wait()
+	e.ctx, e.cancel = nil, nil
 }
 
 func (pi PriceInfo) String() string {
-	return fmt.Sprintf("{PriceInfo Price:%f LastUpdatedReal:%s LastUpdatedWander:%s}",
-		pi.Price, pi.LastUpdatedReal.String(), pi.LastUpdatedWander.String())
+	return fmt.Sprintf("{PriceInfo Price:%s LastUpdatedReal:%s LastUpdatedWander:%s}",
+		pi.Price.String(), pi.LastUpdatedReal.String(), pi.LastUpdatedWander.String())
 }
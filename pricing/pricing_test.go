@@ -0,0 +1,61 @@
+package pricing
+
+import (
+	"context"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"code.vegaprotocol.io/priceproxy/config"
+)
+
+// blockingFetcher blocks every fetch until its context is cancelled, and takes a while to return after that.
+type blockingFetcher struct {
+	started   chan struct{}
+	once      sync.Once
+	cancelled atomic.Bool
+}
+
+func (f *blockingFetcher) Fetch(ctx context.Context, prices config.PriceList) (*FetchResult, error) {
+	f.once.Do(func() { close(f.started) })
+	<-ctx.Done()
+	time.Sleep(50 * time.Millisecond)
+	f.cancelled.Store(true)
+	return nil, ctx.Err()
+}
+
+func TestStopCancelsFetches(t *testing.T) {
+	price := config.PriceConfig{Source: "a", Base: "BTC", Quote: "USD", Factor: 1}
+
+	fetchers := make(chan *blockingFetcher, 2)
+	registerTestFetcher(t, "blocking", func(sourcecfg config.SourceConfig) (Fetcher, error) {
+		fetcher := &blockingFetcher{started: make(chan struct{})}
+		fetchers <- fetcher
+		return fetcher, nil
+	})
+
+	e := NewEngine(config.PriceList{price})
+	require.NoError(t, e.AddSource(config.SourceConfig{Name: "a", Type: "blocking", SleepReal: 1, URL: url.URL{Host: "a"}}))
+	require.NoError(t, e.StartFetching(context.Background()))
+	fetcher := <-fetchers
+
+	select {
+	case <-fetcher.started:
+	case <-time.After(5 * time.Second):
+		t.Fatal("fetch not started")
+	}
+	assert.False(t, fetcher.cancelled.Load())
+
+	e.Stop()
+	assert.True(t, fetcher.cancelled.Load())
+
+	// stopping again is harmless, and fetching can start again
+	e.Stop()
+	require.NoError(t, e.StartFetching(context.Background()))
+	e.Stop()
+}
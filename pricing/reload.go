@@ -0,0 +1,230 @@
+package pricing
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"time"
+
+	"code.vegaprotocol.io/priceproxy/config"
+	"github.com/shopspring/decimal"
+	log "github.com/sirupsen/logrus"
+)
+
+// Reload replaces the configured prices and sources, e.g. after the config file changed.
+//
+// Fetchers of removed and changed sources are stopped, and fetchers of new and changed sources are started
+// unless they are paused. Unchanged sources keep running, and pick up their new prices at their next fetch.
+// Prices which are in both the old and the new config keep their current value, history and validation state,
+// new prices start at zero.
+// If a new source is invalid, an error is returned and nothing is changed.
+func (e *engine) Reload(prices config.PriceList, sources []config.SourceConfig) error {
+	e.runningMu.Lock()
+	defer e.runningMu.Unlock()
+
+	newSources := make(map[string]config.SourceConfig, len(sources))
+	for _, sourcecfg := range sources {
+		if err := checkSource(sourcecfg); err != nil {
+			return fmt.Errorf("source %s: %w", sourcecfg.Name, err)
+		}
+		if _, found := newSources[sourcecfg.Name]; found {
+			return fmt.Errorf("source already exists: %s", sourcecfg.Name)
+		}
+		newSources[sourcecfg.Name] = sourcecfg
+	}
+
+	e.sourcesMu.Lock()
+	oldSources := e.sources
+	e.sourcesMu.Unlock()
+
+	var added, removed, changed []string
+	for name, sourcecfg := range newSources {
+		oldcfg, found := oldSources[name]
+		switch {
+		case !found:
+			added = append(added, name)
+		case !reflect.DeepEqual(oldcfg, sourcecfg):
+			changed = append(changed, name)
+		}
+	}
+	for name := range oldSources {
+		if _, found := newSources[name]; !found {
+			removed = append(removed, name)
+		}
+	}
+
+	// create the new fetchers before stopping anything, so that a failure leaves the engine as it was
+	starting := make([]config.SourceConfig, 0, len(added)+len(changed))
+	for _, name := range append(append([]string{}, added...), changed...) {
+		starting = append(starting, newSources[name])
+	}
+	fetchers, err := newFetchers(starting)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range append(append([]string{}, removed...), changed...) {
+		if runner, found := e.runners[name]; found {
+			runner.stop()
+			delete(e.runners, name)
+		}
+	}
+
+	e.pricesMu.Lock()
+	e.sourcesMu.Lock()
+	e.sources = newSources
+	e.sourcesMu.Unlock()
+	e.reloadPrices(prices)
+	e.pricesMu.Unlock()
+
+	e.ratesMu.Lock()
+	for _, name := range append(append([]string{}, removed...), changed...) {
+		delete(e.rates, name)
+	}
+	for _, name := range removed {
+		delete(e.sourceHealth, name)
+		e.metrics.forgetSource(name)
+	}
+	e.ratesMu.Unlock()
+
+	for _, name := range removed {
+		delete(e.paused, name)
+	}
+	if e.cancel != nil {
+		for _, sourcecfg := range starting {
+			if !e.paused[sourcecfg.Name] {
+				e.runners[sourcecfg.Name] = e.startSource(sourcecfg, fetchers[sourcecfg.Name])
+			}
+		}
+	}
+
+	log.WithFields(log.Fields{
+		"added":   added,
+		"removed": removed,
+		"changed": changed,
+		"prices":  len(prices),
+	}).Info("Reloaded sources and prices")
+
+	return nil
+}
+
+// reloadPrices replaces the price list, keeping the state of prices found in both lists. It must be called
+// with pricesMu held.
+func (e *engine) reloadPrices(prices config.PriceList) {
+	oldPrices := e.priceList
+	e.setFetchList(prices)
+
+	published := make(map[config.PriceKey]bool, len(prices))
+	for _, price := range prices {
+		key := price.Key()
+		published[key] = true
+		if _, found := e.prices[key]; found {
+			continue
+		}
+		if pi, found := e.componentPrices[key]; found {
+			// a component which is now published on its own
+			e.prices[key] = pi
+			if _, found := e.realPrices[key]; !found {
+				e.realPrices[key] = pi
+			}
+			continue
+		}
+		e.prices[key] = PriceInfo{
+			Price:             decimal.Zero,
+			LastUpdatedReal:   time.Unix(0, 0),
+			LastUpdatedWander: time.Now(),
+		}
+	}
+
+	for _, price := range oldPrices {
+		key := price.Key()
+		if !published[key] {
+			delete(e.prices, key)
+			delete(e.histories, key)
+			delete(e.candles, key)
+			delete(e.windows, key)
+			e.metrics.forgetPrice(price)
+		}
+	}
+	for key := range e.componentPrices {
+		if _, found := e.aggregates[key]; !found {
+			delete(e.componentPrices, key)
+		}
+	}
+	// the real price of a component is kept, and it stays overridden by its scenario
+	for key := range e.realPrices {
+		if !published[key] && e.aggregates[key] == nil {
+			delete(e.realPrices, key)
+			delete(e.overrides, key)
+		}
+	}
+	e.validator.forget(func(key config.PriceKey) bool {
+		return !published[key] && e.aggregates[key] == nil
+	})
+}
+
+// PauseSource stops fetching (and wandering) the prices of a source, until ResumeSource is called. Its prices
+// keep their last value, and go stale as usual.
+func (e *engine) PauseSource(name string) error {
+	e.runningMu.Lock()
+	defer e.runningMu.Unlock()
+
+	if _, err := e.GetSource(name); err != nil {
+		return err
+	}
+	if e.paused[name] {
+		return fmt.Errorf("source already paused: %s", name)
+	}
+
+	if runner, found := e.runners[name]; found {
+		runner.stop()
+		delete(e.runners, name)
+	}
+	e.paused[name] = true
+
+	log.WithFields(log.Fields{
+		"sourceName": name,
+	}).Info("Paused source")
+	return nil
+}
+
+// ResumeSource starts fetching the prices of a paused source again.
+func (e *engine) ResumeSource(name string) error {
+	e.runningMu.Lock()
+	defer e.runningMu.Unlock()
+
+	sourcecfg, err := e.GetSource(name)
+	if err != nil {
+		return err
+	}
+	if !e.paused[name] {
+		return fmt.Errorf("source not paused: %s", name)
+	}
+
+	if e.cancel != nil {
+		fetcher, err := newFetcher(sourcecfg)
+		if err != nil {
+			return fmt.Errorf("failed to create fetcher for source %s: %w", name, err)
+		}
+		e.runners[name] = e.startSource(sourcecfg, fetcher)
+	}
+	delete(e.paused, name)
+
+	log.WithFields(log.Fields{
+		"sourceName": name,
+	}).Info("Resumed source")
+	return nil
+}
+
+// PausedSources returns the names of the paused sources.
+func (e *engine) PausedSources() []string {
+	e.runningMu.Lock()
+	defer e.runningMu.Unlock()
+
+	result := make([]string, 0, len(e.paused))
+	for name := range e.paused {
+		result = append(result, name)
+	}
+	sort.Strings(result)
+	return result
+}
@@ -0,0 +1,123 @@
+package pricing
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"code.vegaprotocol.io/priceproxy/config"
+)
+
+type staticFetcher struct {
+	price decimal.Decimal
+}
+
+func (f *staticFetcher) Fetch(ctx context.Context, prices config.PriceList) (*FetchResult, error) {
+	result := newFetchResult()
+	for _, price := range prices {
+		result.Prices[price] = PriceInfo{Price: f.price, LastUpdatedReal: time.Now()}
+	}
+	return result, nil
+}
+
+func init() {
+	_ = RegisterFetcher("static", func(sourcecfg config.SourceConfig) (Fetcher, error) {
+		price, err := decimal.NewFromString(sourcecfg.URL.Host)
+		return &staticFetcher{price: price}, err
+	})
+}
+
+func staticSource(name, price string) config.SourceConfig {
+	return config.SourceConfig{Name: name, Type: "static", SleepReal: 1, URL: url.URL{Host: price}}
+}
+
+func TestReload(t *testing.T) {
+	kept := config.PriceConfig{Source: "a", Base: "BTC", Quote: "USD", Factor: 1}
+	removed := config.PriceConfig{Source: "a", Base: "ETH", Quote: "USD", Factor: 1}
+	added := config.PriceConfig{Source: "b", Base: "ETH", Quote: "USD", Factor: 1}
+	aggregated := func() config.PriceConfig {
+		return config.PriceConfig{
+			Source: "aggregate", Base: "BTC", Quote: "USD", Factor: 1,
+			Aggregate: &config.AggregateConfig{Sources: []config.AggregateSource{{Source: "a"}}},
+		}
+	}
+
+	e := NewEngine(config.PriceList{kept, removed, aggregated()}).(*engine)
+	require.NoError(t, e.AddSource(staticSource("a", "100")))
+	require.NoError(t, e.StartFetching(context.Background()))
+	defer e.Stop()
+
+	require.Eventually(t, func() bool {
+		pi, _ := e.GetPrice(kept)
+		return pi.Price.Equal(decimal.NewFromInt(100))
+	}, 5*time.Second, 10*time.Millisecond)
+
+	// an invalid source leaves everything as it was
+	invalid := staticSource("c", "1")
+	invalid.Type = "unknown"
+	assert.Error(t, e.Reload(config.PriceList{kept}, []config.SourceConfig{staticSource("a", "100"), invalid}))
+	_, err := e.GetPrice(removed)
+	assert.NoError(t, err)
+
+	newAggregated := aggregated()
+	require.NoError(t, e.Reload(
+		config.PriceList{kept, added, newAggregated},
+		[]config.SourceConfig{staticSource("a", "100"), staticSource("b", "200")},
+	))
+
+	prices := e.GetPrices()
+	assert.Len(t, prices, 3)
+	assert.Equal(t, "100", prices[kept].Price.String())
+	_, found := prices[removed]
+	assert.False(t, found)
+
+	// the unchanged aggregated price is found by its new config, and keeps its value and history
+	pi, err := e.GetPrice(newAggregated)
+	require.NoError(t, err)
+	assert.Equal(t, "100", pi.Price.String())
+	history, err := e.GetPriceHistory(newAggregated, time.Time{}, time.Time{})
+	require.NoError(t, err)
+	assert.NotEmpty(t, history)
+	assert.Equal(t, pi, prices[newAggregated])
+
+	require.Eventually(t, func() bool {
+		pi, _ := e.GetPrice(added)
+		return pi.Price.Equal(decimal.NewFromInt(200))
+	}, 5*time.Second, 10*time.Millisecond)
+
+	sources, err := e.GetSources()
+	require.NoError(t, err)
+	assert.Len(t, sources, 2)
+}
+
+func TestPauseSource(t *testing.T) {
+	price := config.PriceConfig{Source: "a", Base: "BTC", Quote: "USD", Factor: 1}
+
+	e := NewEngine(config.PriceList{price}).(*engine)
+	require.NoError(t, e.AddSource(staticSource("a", "100")))
+	require.NoError(t, e.PauseSource("a"))
+	assert.Error(t, e.PauseSource("a"))
+	assert.Error(t, e.PauseSource("unknown"))
+	assert.Equal(t, []string{"a"}, e.PausedSources())
+
+	require.NoError(t, e.StartFetching(context.Background()))
+	defer e.Stop()
+	assert.Empty(t, e.runners)
+
+	// a paused source stays paused when the config is reloaded
+	require.NoError(t, e.Reload(config.PriceList{price}, []config.SourceConfig{staticSource("a", "200")}))
+	assert.Empty(t, e.runners)
+
+	require.NoError(t, e.ResumeSource("a"))
+	assert.Error(t, e.ResumeSource("a"))
+	assert.Empty(t, e.PausedSources())
+	require.Eventually(t, func() bool {
+		pi, _ := e.GetPrice(price)
+		return pi.Price.Equal(decimal.NewFromInt(200))
+	}, 5*time.Second, 10*time.Millisecond)
+}
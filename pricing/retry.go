@@ -0,0 +1,160 @@
+package pricing
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	// maxBackoff caps the exponential backoff after repeated rate limit and server errors.
+	maxBackoff = 30 * time.Minute
+
+	// authFailurePause is how long a source is left alone after rejecting the credentials, e.g. because
+	// the API key is missing or wrong. Retrying sooner would not help, and may get the key banned.
+	authFailurePause = time.Hour
+)
+
+// FetchErrorKind classifies why a fetch failed. It selects how long to wait before retrying.
+type FetchErrorKind string
+
+const (
+	// FetchErrorNetwork is used when the upstream could not be reached, or the connection broke.
+	FetchErrorNetwork FetchErrorKind = "network"
+
+	// FetchErrorClient is used for 4xx responses, except 429.
+	FetchErrorClient FetchErrorKind = "4xx"
+
+	// FetchErrorRateLimited is used for 429 responses.
+	FetchErrorRateLimited FetchErrorKind = "429"
+
+	// FetchErrorServer is used for 5xx responses.
+	FetchErrorServer FetchErrorKind = "5xx"
+
+	// FetchErrorParse is used when the upstream response could not be decoded.
+	FetchErrorParse FetchErrorKind = "parse"
+)
+
+// FetchError is returned by fetchers when a fetch fails. Fetchers registered with RegisterFetcher should
+// return it too, so that their failures get the right retry delay. Other errors are retried like network errors.
+type FetchError struct {
+	Kind       FetchErrorKind
+	StatusCode int
+
+	// RetryAfter is the delay requested by the upstream with a Retry-After header, if any.
+	RetryAfter time.Duration
+
+	Err error
+}
+
+func (e *FetchError) Error() string {
+	if e.StatusCode != 0 {
+		return fmt.Sprintf("%s error (status %d): %s", e.Kind, e.StatusCode, e.Err.Error())
+	}
+	return fmt.Sprintf("%s error: %s", e.Kind, e.Err.Error())
+}
+
+func (e *FetchError) Unwrap() error {
+	return e.Err
+}
+
+// newStatusError classifies a non-200 response.
+func newStatusError(resp *http.Response) *FetchError {
+	fetchErr := &FetchError{
+		Kind:       FetchErrorClient,
+		StatusCode: resp.StatusCode,
+		Err:        fmt.Errorf("expected status 200, got %d", resp.StatusCode),
+	}
+
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		fetchErr.Kind = FetchErrorRateLimited
+	case resp.StatusCode >= 500:
+		fetchErr.Kind = FetchErrorServer
+	}
+
+	if fetchErr.Kind == FetchErrorRateLimited || fetchErr.Kind == FetchErrorServer {
+		fetchErr.RetryAfter = parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+	}
+
+	return fetchErr
+}
+
+// parseRetryAfter parses a Retry-After header, given either in seconds or as an HTTP date.
+func parseRetryAfter(value string, now time.Time) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+
+	if at, err := http.ParseTime(value); err == nil && at.After(now) {
+		return at.Sub(now)
+	}
+
+	return 0
+}
+
+// isSourceWide returns true for errors which would happen again for any request to the source, i.e. rate
+// limit and credentials errors.
+func isSourceWide(err error) bool {
+	var fetchErr *FetchError
+	if !errors.As(err, &fetchErr) {
+		return false
+	}
+	return fetchErr.Kind == FetchErrorRateLimited ||
+		fetchErr.StatusCode == http.StatusUnauthorized || fetchErr.StatusCode == http.StatusForbidden
+}
+
+// retryDelay returns how long to wait after a failed fetch, on top of the usual sleepReal pacing.
+// failures is the number of consecutive failed fetches, including this one.
+//
+// Rate limit and server errors back off exponentially from period, with jitter, unless the upstream asked
+// for a specific delay with Retry-After. 401 and 403 responses pause the source for authFailurePause.
+// Other errors are retried at the usual pace.
+func retryDelay(err error, failures int, period time.Duration) time.Duration {
+	var fetchErr *FetchError
+	if !errors.As(err, &fetchErr) {
+		return 0
+	}
+
+	switch fetchErr.Kind {
+	case FetchErrorClient:
+		if fetchErr.StatusCode == http.StatusUnauthorized || fetchErr.StatusCode == http.StatusForbidden {
+			return authFailurePause
+		}
+		return 0
+
+	case FetchErrorRateLimited, FetchErrorServer:
+		if fetchErr.RetryAfter > 0 {
+			return fetchErr.RetryAfter
+		}
+		return backoff(failures, period, rand.Float64()) // nolint:gosec
+
+	default:
+		return 0
+	}
+}
+
+// backoff doubles period for each consecutive failure, up to maxBackoff, and keeps a random part of the
+// second half of it (r is in [0, 1)), so that sources failing together do not retry together.
+func backoff(failures int, period time.Duration, r float64) time.Duration {
+	if period <= 0 {
+		period = time.Second
+	}
+
+	delay := period
+	for i := 1; i < failures && delay < maxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > maxBackoff {
+		delay = maxBackoff
+	}
+
+	return delay/2 + time.Duration(r*float64(delay/2))
+}
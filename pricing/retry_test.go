@@ -0,0 +1,84 @@
+package pricing
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"code.vegaprotocol.io/priceproxy/config"
+)
+
+func TestFetchJSONClassifiesErrors(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/limited", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "120")
+		w.WriteHeader(http.StatusTooManyRequests)
+	})
+	mux.HandleFunc("/unauthorized", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusUnauthorized)
+	})
+	mux.HandleFunc("/broken", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	})
+	mux.HandleFunc("/garbage", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("<html>"))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client, err := newSourceClient(config.SourceConfig{Name: "test"})
+	require.NoError(t, err)
+
+	var doc interface{}
+	var fetchErr *FetchError
+
+	err = client.fetchJSON(context.Background(), server.URL+"/limited", &doc)
+	require.True(t, errors.As(err, &fetchErr))
+	assert.Equal(t, FetchErrorRateLimited, fetchErr.Kind)
+	assert.Equal(t, 2*time.Minute, fetchErr.RetryAfter)
+	assert.Equal(t, 2*time.Minute, retryDelay(err, 1, time.Minute))
+	assert.True(t, isSourceWide(err))
+
+	err = client.fetchJSON(context.Background(), server.URL+"/unauthorized", &doc)
+	require.True(t, errors.As(err, &fetchErr))
+	assert.Equal(t, FetchErrorClient, fetchErr.Kind)
+	assert.Equal(t, authFailurePause, retryDelay(err, 1, time.Minute))
+	assert.True(t, isSourceWide(err))
+
+	err = client.fetchJSON(context.Background(), server.URL+"/broken", &doc)
+	require.True(t, errors.As(err, &fetchErr))
+	assert.Equal(t, FetchErrorServer, fetchErr.Kind)
+	delay := retryDelay(err, 3, time.Minute)
+	assert.True(t, delay >= 2*time.Minute && delay < 4*time.Minute, delay)
+	assert.False(t, isSourceWide(err))
+
+	err = client.fetchJSON(context.Background(), server.URL+"/garbage", &doc)
+	require.True(t, errors.As(err, &fetchErr))
+	assert.Equal(t, FetchErrorParse, fetchErr.Kind)
+	assert.Zero(t, retryDelay(err, 1, time.Minute))
+
+	server.Close()
+	err = client.fetchJSON(context.Background(), server.URL+"/limited", &doc)
+	require.True(t, errors.As(err, &fetchErr))
+	assert.Equal(t, FetchErrorNetwork, fetchErr.Kind)
+}
+
+func TestBackoff(t *testing.T) {
+	assert.Equal(t, 30*time.Second, backoff(1, time.Minute, 0))
+	assert.Equal(t, 3*time.Minute, backoff(3, time.Minute, 0.5))
+	assert.Equal(t, maxBackoff/2, backoff(100, time.Minute, 0))
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2022, 11, 9, 12, 0, 0, 0, time.UTC)
+	assert.Equal(t, 30*time.Second, parseRetryAfter("30", now))
+	assert.Equal(t, time.Minute, parseRetryAfter("Wed, 09 Nov 2022 12:01:00 GMT", now))
+	assert.Zero(t, parseRetryAfter("Wed, 09 Nov 2022 11:00:00 GMT", now))
+	assert.Zero(t, parseRetryAfter("soon", now))
+}
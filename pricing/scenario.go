@@ -0,0 +1,324 @@
+package pricing
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/shopspring/decimal"
+	log "github.com/sirupsen/logrus"
+
+	"code.vegaprotocol.io/priceproxy/config"
+)
+
+// scenarioTick is how often the prices overridden by a scenario are moved.
+const scenarioTick = time.Second
+
+// ScenarioStatus describes a running scenario. Prices are the prices it still overrides, and Ends is when the
+// last of them returns to its real price.
+type ScenarioStatus struct {
+	Name    string
+	Started time.Time
+	Ends    time.Time
+	Prices  config.PriceList
+}
+
+// scenarioRun is a running scenario, with the timeline of each price it still overrides.
+type scenarioRun struct {
+	name      string
+	started   time.Time
+	ends      time.Time
+	cancel    context.CancelFunc
+	timelines map[config.PriceKey]*scenarioTimeline
+}
+
+// scenarioTimeline is the timeline of one price, from the price it started at.
+type scenarioTimeline struct {
+	price config.PriceConfig
+	steps []config.ScenarioStep
+	start decimal.Decimal
+}
+
+// level returns the price after elapsed, given the current real price. It returns false once the timeline is
+// over, and the price is back to its real price.
+func (tl *scenarioTimeline) level(real decimal.Decimal, elapsed time.Duration) (decimal.Decimal, bool) {
+	level := tl.start
+	for _, step := range tl.steps {
+		duration := time.Duration(step.Duration) * time.Second
+		change := decimal.NewFromFloat(1 + step.Change/100)
+
+		switch step.Action {
+		case config.ScenarioActionGap:
+			level = level.Mul(change).Round(divisionPrecision)
+			continue
+		case config.ScenarioActionReal:
+			if elapsed < duration {
+				return interpolate(level, real, elapsed, duration), true
+			}
+			return real, false
+		}
+
+		if elapsed < duration {
+			switch step.Action {
+			case config.ScenarioActionRamp:
+				return interpolate(level, level.Mul(change), elapsed, duration), true
+			case config.ScenarioActionOscillate:
+				swing := step.Amplitude / 100 * math.Sin(2*math.Pi*elapsed.Seconds()/float64(step.Period))
+				return level.Mul(decimal.NewFromFloat(1 + swing)).Round(divisionPrecision), true
+			default:
+				return level, true
+			}
+		}
+
+		elapsed -= duration
+		if step.Action == config.ScenarioActionRamp {
+			level = level.Mul(change).Round(divisionPrecision)
+		}
+	}
+	return level, false
+}
+
+// duration returns how long the timeline lasts.
+func (tl *scenarioTimeline) duration() time.Duration {
+	total := time.Duration(0)
+	for _, step := range tl.steps {
+		total += time.Duration(step.Duration) * time.Second
+		if step.Action == config.ScenarioActionReal {
+			break
+		}
+	}
+	return total
+}
+
+func interpolate(from, to decimal.Decimal, elapsed, duration time.Duration) decimal.Decimal {
+	return from.Add(to.Sub(from).Mul(decimal.NewFromInt(int64(elapsed))).DivRound(decimal.NewFromInt(int64(duration)), divisionPrecision))
+}
+
+// StartScenario starts overriding the prices of a scenario, from their current value. Every pair of the
+// scenario must match at least one price which was fetched already, and which is not overridden by another
+// scenario. A pair matches the published prices with its source, base and quote, and the component of the
+// same name that aggregated, derived and synthetic prices are calculated from, so that these follow the
+// scenario too.
+func (e *engine) StartScenario(scenariocfg config.ScenarioConfig) error {
+	e.pricesMu.Lock()
+	defer e.pricesMu.Unlock()
+
+	if _, found := e.scenarios[scenariocfg.Name]; found {
+		return fmt.Errorf("scenario already running: %s", scenariocfg.Name)
+	}
+
+	now := time.Now().Round(0)
+	run := &scenarioRun{
+		name:      scenariocfg.Name,
+		started:   now,
+		ends:      now,
+		timelines: make(map[config.PriceKey]*scenarioTimeline),
+	}
+	for _, scenarioPrice := range scenariocfg.Prices {
+		matched := false
+		for _, price := range e.scenarioTargets() {
+			if price.Source != scenarioPrice.Source || price.Base != scenarioPrice.Base || price.Quote != scenarioPrice.Quote {
+				continue
+			}
+			matched = true
+			key := price.Key()
+			if other, found := e.overrides[key]; found {
+				return fmt.Errorf("price %s is already overridden by scenario %s", price.String(), other)
+			}
+			if _, found := e.realPrices[key]; !found {
+				return fmt.Errorf("price not fetched yet: %s", price.String())
+			}
+
+			start, published := e.prices[key]
+			if !published {
+				start = e.componentPrices[key]
+			}
+			timeline := &scenarioTimeline{price: price, steps: scenarioPrice.Steps, start: start.Price}
+			run.timelines[key] = timeline
+			if ends := now.Add(timeline.duration()); ends.After(run.ends) {
+				run.ends = ends
+			}
+		}
+		if !matched {
+			return fmt.Errorf("price not found: %s:%s/%s", scenarioPrice.Source, scenarioPrice.Base, scenarioPrice.Quote)
+		}
+	}
+
+	var ctx context.Context
+	ctx, run.cancel = context.WithCancel(context.Background())
+	e.scenarios[run.name] = run
+	for key := range run.timelines {
+		e.overrides[key] = run.name
+	}
+	e.moveScenarioPrices(run, now)
+
+	e.scenarioRuns.Add(1)
+	go func() {
+		defer e.scenarioRuns.Done()
+		e.runScenario(ctx, run)
+	}()
+
+	log.WithFields(log.Fields{
+		"scenario": run.name,
+		"prices":   len(run.timelines),
+		"ends":     run.ends,
+	}).Info("Started scenario")
+	return nil
+}
+
+// StopScenario stops a running scenario, and returns the prices it overrides to their real price.
+func (e *engine) StopScenario(name string) error {
+	e.pricesMu.Lock()
+	defer e.pricesMu.Unlock()
+
+	run, found := e.scenarios[name]
+	if !found {
+		return fmt.Errorf("scenario not running: %s", name)
+	}
+
+	for _, timeline := range run.timelines {
+		e.endOverride(timeline.price)
+	}
+	e.endScenario(run)
+
+	log.WithFields(log.Fields{
+		"scenario": name,
+	}).Info("Stopped scenario")
+	return nil
+}
+
+// GetScenarios returns the running scenarios, by name.
+func (e *engine) GetScenarios() []ScenarioStatus {
+	e.pricesMu.RLock()
+	defer e.pricesMu.RUnlock()
+
+	result := make([]ScenarioStatus, 0, len(e.scenarios))
+	for _, run := range e.scenarios {
+		status := ScenarioStatus{
+			Name:    run.name,
+			Started: run.started,
+			Ends:    run.ends,
+			Prices:  make(config.PriceList, 0, len(run.timelines)),
+		}
+		for _, timeline := range run.timelines {
+			status.Prices = append(status.Prices, timeline.price)
+		}
+		sort.Slice(status.Prices, func(i, j int) bool { return status.Prices[i].String() < status.Prices[j].String() })
+		result = append(result, status)
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i].Name < result[j].Name })
+	return result
+}
+
+func (e *engine) runScenario(ctx context.Context, run *scenarioRun) {
+	ticker := time.NewTicker(scenarioTick)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		e.pricesMu.Lock()
+		running := e.scenarios[run.name] == run && e.moveScenarioPrices(run, time.Now().Round(0))
+		e.pricesMu.Unlock()
+		if !running {
+			return
+		}
+	}
+}
+
+// scenarioTargets returns the prices a scenario may override: the published prices, and the components which
+// are not published on their own. It must be called with pricesMu held.
+func (e *engine) scenarioTargets() config.PriceList {
+	targets := append(config.PriceList{}, e.priceList...)
+	for _, price := range e.fetchList {
+		if e.hidden[price.Key()] {
+			targets = append(targets, price)
+		}
+	}
+	return targets
+}
+
+// moveScenarioPrices publishes the prices of a scenario at now, and ends the timelines which are over. It
+// returns false once the scenario is over. It must be called with pricesMu held.
+func (e *engine) moveScenarioPrices(run *scenarioRun, now time.Time) bool {
+	for key, timeline := range run.timelines {
+		price := timeline.price
+		real, found := e.realPrices[key]
+		if _, published := e.prices[key]; !found || !published && !e.hidden[key] {
+			// removed by a reload
+			delete(run.timelines, key)
+			delete(e.overrides, key)
+			continue
+		}
+
+		level, ok := timeline.level(real.Price, now.Sub(run.started))
+		if !ok {
+			e.endOverride(price)
+			delete(run.timelines, key)
+			continue
+		}
+
+		moved := real
+		if current, published := e.prices[key]; published {
+			moved = current
+		}
+		moved.Price = level
+		moved.LastUpdatedReal = real.LastUpdatedReal
+		moved.LastUpdatedWander = now
+		moved.Scenario = run.name
+		e.overridePrice(price, moved, updateScenario)
+	}
+
+	if len(run.timelines) == 0 {
+		e.endScenario(run)
+		log.WithFields(log.Fields{
+			"scenario": run.name,
+		}).Info("Finished scenario")
+		return false
+	}
+	return true
+}
+
+// endOverride returns a price overridden by a scenario to its real price. It must be called with pricesMu
+// held.
+func (e *engine) endOverride(price config.PriceConfig) {
+	key := price.Key()
+	delete(e.overrides, key)
+	if real, found := e.realPrices[key]; found {
+		e.overridePrice(price, real, updateReal)
+	}
+}
+
+// overridePrice replaces a published or component price, and recalculates the prices calculated from it. It
+// must be called with pricesMu held.
+func (e *engine) overridePrice(price config.PriceConfig, pi PriceInfo, kind updateKind) {
+	key := price.Key()
+	if _, published := e.prices[key]; published {
+		e.prices[key] = pi
+		e.recordHistory(price, pi, kind)
+		e.metrics.priceUpdated(price, kind)
+		e.publish(price, pi)
+	}
+
+	if dependents, found := e.aggregates[key]; found {
+		if price.Fetched() {
+			e.componentPrices[key] = pi
+		}
+		e.updateAggregates(dependents)
+	}
+}
+
+// endScenario forgets a scenario and stops its goroutine. It must be called with pricesMu held.
+func (e *engine) endScenario(run *scenarioRun) {
+	for key := range run.timelines {
+		delete(e.overrides, key)
+	}
+	delete(e.scenarios, run.name)
+	run.cancel()
+}
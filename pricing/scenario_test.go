@@ -0,0 +1,247 @@
+package pricing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"code.vegaprotocol.io/priceproxy/config"
+)
+
+func TestScenarioTimeline(t *testing.T) {
+	timeline := &scenarioTimeline{
+		start: decimal.NewFromInt(100),
+		steps: []config.ScenarioStep{
+			{Action: config.ScenarioActionGap, Change: -10},
+			{Action: config.ScenarioActionRamp, Change: -50, Duration: 100},
+			{Action: config.ScenarioActionHold, Duration: 60},
+			{Action: config.ScenarioActionOscillate, Amplitude: 10, Period: 40, Duration: 40},
+			{Action: config.ScenarioActionReal, Duration: 20},
+			{Action: config.ScenarioActionHold, Duration: 1000},
+		},
+	}
+	assert.Equal(t, 220*time.Second, timeline.duration())
+
+	real := decimal.NewFromInt(100)
+	for seconds, expected := range map[int]string{
+		0:   "90",
+		50:  "67.5",
+		100: "45",
+		159: "45",
+		170: "49.5",
+		200: "45",
+		210: "72.5",
+	} {
+		level, ok := timeline.level(real, time.Duration(seconds)*time.Second)
+		assert.True(t, ok, seconds)
+		assert.Equal(t, expected, level.String(), seconds)
+	}
+
+	level, ok := timeline.level(real, 220*time.Second)
+	assert.False(t, ok)
+	assert.Equal(t, "100", level.String())
+}
+
+func TestScenarios(t *testing.T) {
+	fetched := config.PriceConfig{Source: "a", Base: "BTC", Quote: "USD", Factor: 1}
+	wandered := config.PriceConfig{Source: "a", Base: "BTC", Quote: "USD", Factor: 2, Wander: true}
+	never := config.PriceConfig{Source: "a", Base: "ETH", Quote: "USD", Factor: 1}
+	e := NewEngine(config.PriceList{fetched, wandered, never}).(*engine)
+	e.UpdatePrice(fetched, PriceInfo{Price: decimal.NewFromInt(100), LastUpdatedReal: time.Now()})
+	e.UpdatePrice(wandered, PriceInfo{Price: decimal.NewFromInt(100), LastUpdatedReal: time.Now()})
+
+	crash := config.ScenarioConfig{
+		Name: "crash",
+		Prices: []config.ScenarioPriceConfig{{
+			Source: "a", Base: "BTC", Quote: "USD",
+			Steps: []config.ScenarioStep{
+				{Action: config.ScenarioActionGap, Change: -50},
+				{Action: config.ScenarioActionHold, Duration: 60},
+			},
+		}},
+	}
+	require.NoError(t, e.StartScenario(crash))
+	assert.Error(t, e.StartScenario(crash))
+	other := crash
+	other.Name = "other"
+	assert.Error(t, e.StartScenario(other), "the price is overridden by crash")
+	other.Prices = []config.ScenarioPriceConfig{{Source: "a", Base: "ETH", Quote: "USD", Steps: crash.Prices[0].Steps}}
+	assert.Error(t, e.StartScenario(other), "never fetched")
+	other.Prices[0].Base = "DOGE"
+	assert.Error(t, e.StartScenario(other), "no such price")
+
+	scenarios := e.GetScenarios()
+	require.Len(t, scenarios, 1)
+	assert.Equal(t, "crash", scenarios[0].Name)
+	assert.ElementsMatch(t, config.PriceList{fetched, wandered}, scenarios[0].Prices)
+	assert.Equal(t, scenarios[0].Started.Add(time.Minute), scenarios[0].Ends)
+
+	// real updates are kept, but not published
+	e.UpdatePrice(fetched, PriceInfo{Price: decimal.NewFromInt(110), LastUpdatedReal: time.Now()})
+	for _, price := range []config.PriceConfig{fetched, wandered} {
+		pi, err := e.GetPrice(price)
+		require.NoError(t, err)
+		assert.Equal(t, "50", pi.Price.String())
+		assert.Equal(t, "crash", pi.Scenario)
+	}
+
+	require.NoError(t, e.StopScenario("crash"))
+	assert.Error(t, e.StopScenario("crash"))
+	assert.Empty(t, e.GetScenarios())
+	pi, err := e.GetPrice(fetched)
+	require.NoError(t, err)
+	assert.Equal(t, "110", pi.Price.String())
+	assert.Empty(t, pi.Scenario)
+
+	// a scenario ends by itself at the end of its timelines
+	require.NoError(t, e.StartScenario(crash))
+	e.pricesMu.Lock()
+	run := e.scenarios["crash"]
+	assert.False(t, e.moveScenarioPrices(run, run.started.Add(time.Minute)))
+	e.pricesMu.Unlock()
+	assert.Empty(t, e.GetScenarios())
+	pi, err = e.GetPrice(wandered)
+	require.NoError(t, err)
+	assert.Equal(t, "100", pi.Price.String())
+}
+
+func TestScenarioLongerThanMaxAge(t *testing.T) {
+	price := config.PriceConfig{Source: "a", Base: "BTC", Quote: "USD", Factor: 1, MaxAge: 60}
+	e := NewEngine(config.PriceList{price}).(*engine)
+	e.UpdatePrice(price, PriceInfo{Price: decimal.NewFromInt(100), LastUpdatedReal: time.Now().Round(0)})
+
+	hold := config.ScenarioConfig{
+		Name: "hold",
+		Prices: []config.ScenarioPriceConfig{{
+			Source: "a", Base: "BTC", Quote: "USD",
+			Steps: []config.ScenarioStep{{Action: config.ScenarioActionHold, Duration: 3600}},
+		}},
+	}
+	require.NoError(t, e.StartScenario(hold))
+	defer e.Stop()
+
+	// two minutes into the scenario, the source is still fetched: the moved price is as fresh as the real one
+	e.pricesMu.RLock()
+	run := e.scenarios["hold"]
+	e.pricesMu.RUnlock()
+	later := run.started.Add(2 * time.Minute)
+	e.UpdatePrice(price, PriceInfo{Price: decimal.NewFromInt(110), LastUpdatedReal: later})
+	e.pricesMu.Lock()
+	assert.True(t, e.moveScenarioPrices(run, later))
+	e.pricesMu.Unlock()
+
+	pi, err := e.GetPrice(price)
+	require.NoError(t, err)
+	assert.Equal(t, "100", pi.Price.String())
+	assert.Equal(t, "hold", pi.Scenario)
+	assert.Equal(t, later, pi.LastUpdatedReal)
+	assert.False(t, GetFreshness(pi, e.PriceMaxAge(price), later).Stale)
+
+	// the moves are recorded as scenario moves, not as wandered or real updates
+	points, err := e.GetPriceHistory(price, time.Time{}, time.Time{})
+	require.NoError(t, err)
+	require.Len(t, points, 3)
+	assert.False(t, points[0].Scenario)
+	for _, point := range points[1:] {
+		assert.True(t, point.Scenario)
+		assert.False(t, point.Wandered)
+	}
+	candles, err := e.GetCandles(price, time.Minute, time.Time{}, time.Time{})
+	require.NoError(t, err)
+	require.NotEmpty(t, candles)
+	scenario := 0
+	for _, candle := range candles {
+		scenario += candle.Scenario
+		assert.Zero(t, candle.Wandered)
+	}
+	assert.Equal(t, 2, scenario)
+	assert.Equal(t, 1.0, testutil.ToFloat64(e.metrics.priceUpdates.WithLabelValues("a", "BTC", "USD", "false", "real")))
+	assert.Equal(t, 2.0, testutil.ToFloat64(e.metrics.priceUpdates.WithLabelValues("a", "BTC", "USD", "false", "scenario")))
+}
+
+func TestScenarioDependents(t *testing.T) {
+	a := config.PriceConfig{Source: "a", Base: "BTC", Quote: "USD", Factor: 1}
+	b := config.PriceConfig{Source: "b", Base: "BTC", Quote: "USD", Factor: 1}
+	agg := config.PriceConfig{
+		Source:    "agg",
+		Base:      "BTC",
+		Quote:     "USD",
+		Factor:    1,
+		Aggregate: &config.AggregateConfig{Sources: []config.AggregateSource{{Source: "a"}, {Source: "b"}}},
+	}
+	eur := config.PriceConfig{
+		Source:    "synthetic",
+		Base:      "BTC",
+		Quote:     "EUR",
+		Factor:    1,
+		Synthetic: &config.SyntheticConfig{Expression: "agg:BTC/USD * 0.9"},
+	}
+	twap := config.PriceConfig{
+		Source: "twap",
+		Base:   "BTC",
+		Quote:  "USD",
+		Factor: 1,
+		Derive: &config.DeriveConfig{Method: config.DeriveMethodTWAP, Window: 300, Source: "b"},
+	}
+	e := NewEngine(config.PriceList{a, agg, eur, twap}).(*engine)
+	e.UpdatePrice(a, PriceInfo{Price: decimal.NewFromInt(100), LastUpdatedReal: time.Now()})
+	e.UpdatePrice(b, PriceInfo{Price: decimal.NewFromInt(100), LastUpdatedReal: time.Now()})
+
+	assertPrice := func(price config.PriceConfig, expected, scenario string) {
+		t.Helper()
+		pi, err := e.GetPrice(price)
+		require.NoError(t, err)
+		assert.Equal(t, expected, pi.Price.String(), price.String())
+		assert.Equal(t, scenario, pi.Scenario, price.String())
+	}
+	assertPrice(agg, "100", "")
+	assertPrice(eur, "90", "")
+
+	// b is only fetched for agg and twap, which follow the scenario, and so does the synthetic price on agg
+	crash := config.ScenarioConfig{
+		Name: "crash",
+		Prices: []config.ScenarioPriceConfig{{
+			Source: "b", Base: "BTC", Quote: "USD",
+			Steps: []config.ScenarioStep{
+				{Action: config.ScenarioActionGap, Change: -50},
+				{Action: config.ScenarioActionHold, Duration: 60},
+			},
+		}},
+	}
+	require.NoError(t, e.StartScenario(crash))
+	assert.Equal(t, config.PriceList{b}, e.GetScenarios()[0].Prices)
+	assertPrice(a, "100", "")
+	assertPrice(agg, "75", "crash")
+	assertPrice(eur, "67.5", "crash")
+	pi, err := e.GetPrice(twap)
+	require.NoError(t, err)
+	assert.Equal(t, "crash", pi.Scenario)
+
+	// real updates of b are kept for the end of the scenario
+	e.UpdatePrice(b, PriceInfo{Price: decimal.NewFromInt(200), LastUpdatedReal: time.Now()})
+	assertPrice(agg, "75", "crash")
+
+	require.NoError(t, e.StopScenario("crash"))
+	assertPrice(agg, "150", "")
+	assertPrice(eur, "135", "")
+
+	// the synthetic price follows a scenario on the aggregated price
+	crash.Prices[0].Source = "agg"
+	require.NoError(t, e.StartScenario(crash))
+	assertPrice(agg, "75", "crash")
+	assertPrice(eur, "67.5", "crash")
+
+	e.UpdatePrice(a, PriceInfo{Price: decimal.NewFromInt(300), LastUpdatedReal: time.Now()})
+	assertPrice(agg, "75", "crash")
+	assertPrice(eur, "67.5", "crash")
+
+	// stopping the engine ends the scenarios
+	e.Stop()
+	assert.Empty(t, e.GetScenarios())
+	assertPrice(agg, "250", "")
+	assertPrice(eur, "225", "")
+}
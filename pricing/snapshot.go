@@ -0,0 +1,207 @@
+package pricing
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"code.vegaprotocol.io/priceproxy/config"
+	"github.com/shopspring/decimal"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	defaultSnapshotInterval = time.Minute
+	snapshotVersion         = 1
+)
+
+// snapshotFile is the on-disk format of a price board snapshot.
+type snapshotFile struct {
+	Version int             `json:"version"`
+	Time    time.Time       `json:"time"`
+	Prices  []snapshotPrice `json:"prices"`
+}
+
+// snapshotPrice is one price in a snapshot. Prices are matched back to the config by source, base, quote,
+// overrides and wander setting, so a snapshot survives unrelated config changes.
+type snapshotPrice struct {
+	Source            string          `json:"source"`
+	Base              string          `json:"base"`
+	BaseOverride      string          `json:"base_override,omitempty"`
+	Quote             string          `json:"quote"`
+	QuoteOverride     string          `json:"quote_override,omitempty"`
+	Wander            bool            `json:"wander"`
+	Price             decimal.Decimal `json:"price"`
+	LastUpdatedReal   time.Time       `json:"lastUpdatedReal"`
+	LastUpdatedWander time.Time       `json:"lastUpdatedWander"`
+}
+
+type snapshotKey struct {
+	source, base, baseOverride, quote, quoteOverride string
+	wander                                           bool
+}
+
+func (sp snapshotPrice) key() snapshotKey {
+	return snapshotKey{sp.Source, sp.Base, sp.BaseOverride, sp.Quote, sp.QuoteOverride, sp.Wander}
+}
+
+func newSnapshotPrice(pricecfg config.PriceConfig, pi PriceInfo) snapshotPrice {
+	return snapshotPrice{
+		Source:            pricecfg.Source,
+		Base:              pricecfg.Base,
+		BaseOverride:      pricecfg.BaseOverride,
+		Quote:             pricecfg.Quote,
+		QuoteOverride:     pricecfg.QuoteOverride,
+		Wander:            pricecfg.Wander,
+		Price:             pi.Price,
+		LastUpdatedReal:   pi.LastUpdatedReal,
+		LastUpdatedWander: pi.LastUpdatedWander,
+	}
+}
+
+// WithSnapshot makes the engine save its prices to a file periodically and on Stop, and restore them
+// from that file when it starts fetching.
+func WithSnapshot(snapshotcfg config.SnapshotConfig) EngineOption {
+	return func(e *engine) {
+		e.snapshotPath = snapshotcfg.Path
+		if snapshotcfg.Interval > 0 {
+			e.snapshotInterval = time.Duration(snapshotcfg.Interval) * time.Second
+		}
+	}
+}
+
+// writeSnapshot saves the prices which were fetched at least once, with their real price while a scenario
+// overrides them. The file is replaced atomically, so a crash while writing leaves the previous snapshot in place.
+func (e *engine) writeSnapshot() error {
+	snapshot := snapshotFile{
+		Version: snapshotVersion,
+		Time:    time.Now().Round(0),
+		Prices:  []snapshotPrice{},
+	}
+
+	e.pricesMu.RLock()
+	for _, price := range e.priceList {
+		key := price.Key()
+		pi, found := e.prices[key]
+		if _, overridden := e.overrides[key]; overridden {
+			pi = e.realPrices[key]
+		}
+		if !found || !pi.Price.IsPositive() {
+			continue
+		}
+		snapshot.Prices = append(snapshot.Prices, newSnapshotPrice(price, pi))
+	}
+	e.pricesMu.RUnlock()
+
+	data, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode snapshot: %w", err)
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(e.snapshotPath), filepath.Base(e.snapshotPath)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create snapshot file: %w", err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write snapshot file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write snapshot file: %w", err)
+	}
+	if err := os.Rename(tmp.Name(), e.snapshotPath); err != nil {
+		return fmt.Errorf("failed to replace snapshot file: %w", err)
+	}
+
+	return nil
+}
+
+// readSnapshot returns the prices saved in the snapshot file, by price. A missing file gives no prices.
+func (e *engine) readSnapshot() (map[snapshotKey]snapshotPrice, error) {
+	data, err := os.ReadFile(e.snapshotPath)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read snapshot file: %w", err)
+	}
+
+	var snapshot snapshotFile
+	if err := json.Unmarshal(data, &snapshot); err != nil {
+		return nil, fmt.Errorf("failed to parse snapshot file: %w", err)
+	}
+	if snapshot.Version != snapshotVersion {
+		return nil, fmt.Errorf("unsupported snapshot version: %d", snapshot.Version)
+	}
+
+	prices := make(map[snapshotKey]snapshotPrice, len(snapshot.Prices))
+	for _, sp := range snapshot.Prices {
+		prices[sp.key()] = sp
+	}
+	return prices, nil
+}
+
+// restoreSnapshot publishes the prices saved in the snapshot file, marked as restored. It must be called with
+// pricesMu held. Restored prices are not wandered, and are replaced by the first real update.
+func (e *engine) restoreSnapshot() {
+	restored, err := e.readSnapshot()
+	if err != nil {
+		log.WithFields(log.Fields{
+			"error": err.Error(),
+			"path":  e.snapshotPath,
+		}).Warn("Failed to restore prices from snapshot")
+		return
+	}
+
+	count := 0
+	for _, price := range e.priceList {
+		sp, found := restored[newSnapshotPrice(price, PriceInfo{}).key()]
+		if !found {
+			continue
+		}
+		e.prices[price.Key()] = PriceInfo{
+			Price:             sp.Price,
+			LastUpdatedReal:   sp.LastUpdatedReal,
+			LastUpdatedWander: sp.LastUpdatedWander,
+			Restored:          true,
+		}
+		count++
+	}
+
+	log.WithFields(log.Fields{
+		"path":     e.snapshotPath,
+		"restored": count,
+	}).Info("Restored prices from snapshot")
+}
+
+// snapshotStart saves the prices every snapshotInterval, and once more when ctx is cancelled.
+func (e *engine) snapshotStart(ctx context.Context) {
+	ticker := time.NewTicker(e.snapshotInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			e.logSnapshotError(e.writeSnapshot())
+			return
+		case <-ticker.C:
+			e.logSnapshotError(e.writeSnapshot())
+		}
+	}
+}
+
+func (e *engine) logSnapshotError(err error) {
+	if err != nil {
+		log.WithFields(log.Fields{
+			"error": err.Error(),
+			"path":  e.snapshotPath,
+		}).Warn("Failed to save prices snapshot")
+	}
+}
@@ -0,0 +1,55 @@
+package pricing
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"code.vegaprotocol.io/priceproxy/config"
+)
+
+func TestSnapshotRestore(t *testing.T) {
+	snapshotcfg := config.SnapshotConfig{Path: filepath.Join(t.TempDir(), "prices.json")}
+	fetched := config.PriceConfig{Source: "src", Base: "BTC", Quote: "USD", Factor: 1, Wander: true}
+	neverFetched := config.PriceConfig{Source: "src", Base: "ETH", Quote: "USD", Factor: 1}
+	prices := config.PriceList{fetched, neverFetched}
+
+	lastUpdatedReal := time.Now().Add(-time.Minute).Round(0).UTC()
+	e := NewEngine(prices, WithSnapshot(snapshotcfg)).(*engine)
+	e.initPrices()
+	e.UpdatePrice(fetched, PriceInfo{
+		Price:           decimal.RequireFromString("20123.456789012345678"),
+		LastUpdatedReal: lastUpdatedReal,
+	})
+	require.NoError(t, e.writeSnapshot())
+
+	restarted := NewEngine(prices, WithSnapshot(snapshotcfg)).(*engine)
+	restarted.initPrices()
+
+	pi, err := restarted.GetPrice(fetched)
+	require.NoError(t, err)
+	assert.True(t, pi.Restored)
+	assert.Equal(t, "20123.456789012345678", pi.Price.String())
+	assert.True(t, lastUpdatedReal.Equal(pi.LastUpdatedReal))
+
+	pi, err = restarted.GetPrice(neverFetched)
+	require.NoError(t, err)
+	assert.False(t, pi.Restored)
+	assert.True(t, pi.Price.IsZero())
+
+	// restored prices are not wandered
+	restarted.wanderPrice(fetched, func(current, real PriceInfo) (PriceInfo, bool) {
+		t.Fatal("restored price was wandered")
+		return current, false
+	})
+
+	restarted.UpdatePrice(fetched, PriceInfo{Price: decimal.NewFromInt(20000), LastUpdatedReal: time.Now()})
+	pi, err = restarted.GetPrice(fetched)
+	require.NoError(t, err)
+	assert.False(t, pi.Restored)
+	assert.Equal(t, "20000", pi.Price.String())
+}
@@ -0,0 +1,107 @@
+package pricing
+
+import (
+	"time"
+
+	"code.vegaprotocol.io/priceproxy/config"
+)
+
+// PriceStatus describes how fresh a price is.
+type PriceStatus string
+
+const (
+	// PriceStatusNeverFetched is used for prices which have not been fetched since the engine started.
+	PriceStatusNeverFetched PriceStatus = "never_fetched"
+
+	// PriceStatusFresh is used for prices fetched within their max age.
+	PriceStatusFresh PriceStatus = "fresh"
+
+	// PriceStatusStale is used for prices older than their max age.
+	PriceStatusStale PriceStatus = "stale"
+)
+
+// defaultMaxAgeSleeps is the number of SleepReal periods after which prices from a source are stale,
+// when no max age is configured.
+const defaultMaxAgeSleeps = 3
+
+// Freshness describes how fresh a price is. Age is measured from LastUpdatedReal.
+// Never fetched prices count as stale.
+type Freshness struct {
+	Status PriceStatus
+	Age    time.Duration
+	Stale  bool
+}
+
+// GetFreshness returns how fresh a price is, given its max age (zero for no limit).
+func GetFreshness(pi PriceInfo, maxAge time.Duration, now time.Time) Freshness {
+	if pi.LastUpdatedReal.Unix() <= 0 {
+		return Freshness{
+			Status: PriceStatusNeverFetched,
+			Stale:  true,
+		}
+	}
+
+	age := now.Sub(pi.LastUpdatedReal)
+	if maxAge > 0 && age > maxAge {
+		return Freshness{
+			Status: PriceStatusStale,
+			Age:    age,
+			Stale:  true,
+		}
+	}
+
+	return Freshness{
+		Status: PriceStatusFresh,
+		Age:    age,
+	}
+}
+
+// PriceMaxAge returns the age after which a price is stale: the max age of the price if set, otherwise the one
+// of its source (or of its aggregation, of the price it is derived from, or the longest of the prices it is
+// aggregated or synthesized from), otherwise defaultMaxAgeSleeps times the SleepReal of its source.
+// Zero means the price never goes stale.
+func (e *engine) PriceMaxAge(pricecfg config.PriceConfig) time.Duration {
+	if pricecfg.MaxAge > 0 {
+		return time.Duration(pricecfg.MaxAge) * time.Second
+	}
+
+	if pricecfg.Aggregate != nil && pricecfg.Aggregate.MaxAge > 0 {
+		return time.Duration(pricecfg.Aggregate.MaxAge) * time.Second
+	}
+	if pricecfg.Derive != nil {
+		return e.PriceMaxAge(pricecfg.Derive.PriceConfig(pricecfg))
+	}
+	if pricecfg.Aggregate != nil || pricecfg.Synthetic != nil {
+		return e.componentsMaxAge(pricecfg)
+	}
+
+	e.sourcesMu.Lock()
+	sourcecfg, found := e.sources[pricecfg.Source]
+	e.sourcesMu.Unlock()
+	if !found {
+		return 0
+	}
+
+	if sourcecfg.MaxAge > 0 {
+		return time.Duration(sourcecfg.MaxAge) * time.Second
+	}
+	return time.Duration(defaultMaxAgeSleeps*sourcecfg.SleepReal) * time.Second
+}
+
+// componentsMaxAge returns the longest max age of the prices an aggregated or synthetic price is calculated from.
+func (e *engine) componentsMaxAge(pricecfg config.PriceConfig) time.Duration {
+	e.pricesMu.RLock()
+	prices := e.priceList
+	e.pricesMu.RUnlock()
+
+	maxAge := time.Duration(0)
+	for _, component := range pricecfg.Components() {
+		if dependency, found := prices.Resolve(component); found && pricecfg.Synthetic != nil {
+			component = dependency
+		}
+		if age := e.PriceMaxAge(component); age > maxAge {
+			maxAge = age
+		}
+	}
+	return maxAge
+}
@@ -0,0 +1,101 @@
+package pricing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"code.vegaprotocol.io/priceproxy/config"
+)
+
+func TestGetFreshness(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name        string
+		lastUpdated time.Time
+		maxAge      time.Duration
+		expected    Freshness
+	}{
+		{name: "never fetched", maxAge: time.Minute, expected: Freshness{Status: PriceStatusNeverFetched, Stale: true}},
+		{name: "epoch", lastUpdated: time.Unix(0, 0), expected: Freshness{Status: PriceStatusNeverFetched, Stale: true}},
+		{name: "fresh", lastUpdated: now.Add(-30 * time.Second), maxAge: time.Minute, expected: Freshness{Status: PriceStatusFresh, Age: 30 * time.Second}},
+		{name: "at max age", lastUpdated: now.Add(-time.Minute), maxAge: time.Minute, expected: Freshness{Status: PriceStatusFresh, Age: time.Minute}},
+		{name: "stale", lastUpdated: now.Add(-2 * time.Minute), maxAge: time.Minute, expected: Freshness{Status: PriceStatusStale, Age: 2 * time.Minute, Stale: true}},
+		{name: "no max age", lastUpdated: now.Add(-time.Hour), expected: Freshness{Status: PriceStatusFresh, Age: time.Hour}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, GetFreshness(PriceInfo{LastUpdatedReal: tt.lastUpdated}, tt.maxAge, now))
+		})
+	}
+}
+
+func TestPriceMaxAge(t *testing.T) {
+	a := config.PriceConfig{Source: "a", Base: "BTC", Quote: "USD", Factor: 1}
+	b := config.PriceConfig{Source: "b", Base: "BTC", Quote: "USD", Factor: 1}
+	withMaxAge := config.PriceConfig{Source: "b", Base: "ETH", Quote: "USD", Factor: 1, MaxAge: 45}
+	unknown := config.PriceConfig{Source: "unknown", Base: "BTC", Quote: "USD", Factor: 1}
+	aggSources := []config.AggregateSource{{Source: "a"}, {Source: "b"}}
+	agg := config.PriceConfig{
+		Source:    "agg",
+		Base:      "BTC",
+		Quote:     "USD",
+		Factor:    1,
+		Aggregate: &config.AggregateConfig{MaxAge: 600, Sources: aggSources},
+	}
+	aggNoMaxAge := config.PriceConfig{
+		Source:    "aggnomaxage",
+		Base:      "BTC",
+		Quote:     "USD",
+		Factor:    1,
+		Aggregate: &config.AggregateConfig{Sources: aggSources},
+	}
+	derived := config.PriceConfig{
+		Source: "twap",
+		Base:   "BTC",
+		Quote:  "USD",
+		Factor: 1,
+		Derive: &config.DeriveConfig{Method: config.DeriveMethodTWAP, Window: 300, Source: "b"},
+	}
+	synthetic := config.PriceConfig{
+		Source:    "synthetic",
+		Base:      "BTC",
+		Quote:     "EUR",
+		Factor:    1,
+		Synthetic: &config.SyntheticConfig{Expression: "a:BTC/USD * 0.9"},
+	}
+	chained := config.PriceConfig{
+		Source:    "chained",
+		Base:      "BTC",
+		Quote:     "EUR",
+		Factor:    1,
+		Synthetic: &config.SyntheticConfig{Expression: "synthetic:BTC/EUR + aggnomaxage:BTC/USD"},
+	}
+
+	e := NewEngine(config.PriceList{a, b, withMaxAge, agg, aggNoMaxAge, derived, synthetic, chained})
+	require.NoError(t, e.AddSource(config.SourceConfig{Name: "a", Type: "static", SleepReal: 10}))
+	require.NoError(t, e.AddSource(config.SourceConfig{Name: "b", Type: "static", SleepReal: 10, MaxAge: 120}))
+
+	tests := []struct {
+		name     string
+		price    config.PriceConfig
+		expected time.Duration
+	}{
+		{name: "default from the source sleep", price: a, expected: 30 * time.Second},
+		{name: "source max age", price: b, expected: 2 * time.Minute},
+		{name: "price max age", price: withMaxAge, expected: 45 * time.Second},
+		{name: "unknown source", price: unknown, expected: 0},
+		{name: "aggregate max age", price: agg, expected: 10 * time.Minute},
+		{name: "aggregate without max age", price: aggNoMaxAge, expected: 2 * time.Minute},
+		{name: "derived", price: derived, expected: 2 * time.Minute},
+		{name: "synthetic", price: synthetic, expected: 30 * time.Second},
+		{name: "synthetic of synthetic and aggregate", price: chained, expected: 2 * time.Minute},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.expected, e.PriceMaxAge(tt.price))
+		})
+	}
+}
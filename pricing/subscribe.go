@@ -0,0 +1,109 @@
+package pricing
+
+import (
+	"sync"
+
+	"code.vegaprotocol.io/priceproxy/config"
+	log "github.com/sirupsen/logrus"
+)
+
+// updateReplaySize is how many of the latest updates are kept for UpdatesSince.
+const updateReplaySize = 4096
+
+// PriceUpdate is sent to subscribers whenever a published price changes, fetched or wandered. Seq numbers
+// the updates in the order they were published, starting from 1.
+type PriceUpdate struct {
+	Seq   uint64
+	Price config.PriceConfig
+	Info  PriceInfo
+}
+
+// subscribers holds the channels updates are published to, and the latest updates in a ring indexed by
+// Seq modulo its size.
+type subscribers struct {
+	channels map[chan PriceUpdate]struct{}
+	seq      uint64
+	recent   []PriceUpdate
+	mu       sync.Mutex
+}
+
+func newSubscribers() subscribers {
+	return subscribers{
+		channels: make(map[chan PriceUpdate]struct{}),
+		recent:   make([]PriceUpdate, updateReplaySize),
+	}
+}
+
+// Subscribe returns a channel which receives every update of a published price, and a function to
+// unsubscribe, which closes the channel. The channel buffers up to buffer updates: a subscriber which falls
+// further behind is dropped, and its channel closed, rather than holding up the engine.
+func (e *engine) Subscribe(buffer int) (<-chan PriceUpdate, func()) {
+	ch := make(chan PriceUpdate, buffer)
+
+	e.subscribers.mu.Lock()
+	e.subscribers.channels[ch] = struct{}{}
+	e.subscribers.mu.Unlock()
+
+	return ch, func() {
+		e.subscribers.mu.Lock()
+		defer e.subscribers.mu.Unlock()
+
+		e.subscribers.remove(ch)
+	}
+}
+
+// remove closes the channel of a subscriber, unless already done. It must be called with mu held.
+func (s *subscribers) remove(ch chan PriceUpdate) {
+	if _, found := s.channels[ch]; found {
+		delete(s.channels, ch)
+		close(ch)
+	}
+}
+
+// UpdatesSince returns the updates published after the one numbered seq, oldest first. It returns false if
+// some of them are no longer kept, or if seq was never published.
+func (e *engine) UpdatesSince(seq uint64) ([]PriceUpdate, bool) {
+	e.subscribers.mu.Lock()
+	defer e.subscribers.mu.Unlock()
+
+	last := e.subscribers.seq
+	if seq > last || last-seq > uint64(len(e.subscribers.recent)) {
+		return nil, false
+	}
+
+	updates := make([]PriceUpdate, 0, last-seq)
+	for i := seq + 1; i <= last; i++ {
+		updates = append(updates, e.subscribers.recent[i%uint64(len(e.subscribers.recent))])
+	}
+	return updates, true
+}
+
+// LastUpdateSeq returns the number of the latest update, 0 if none was published yet.
+func (e *engine) LastUpdateSeq() uint64 {
+	e.subscribers.mu.Lock()
+	defer e.subscribers.mu.Unlock()
+
+	return e.subscribers.seq
+}
+
+// publish sends an update to all subscribers, without blocking. It is called with pricesMu held, so that
+// subscribers get the updates of a price in order.
+func (e *engine) publish(pricecfg config.PriceConfig, pi PriceInfo) {
+	e.subscribers.mu.Lock()
+	defer e.subscribers.mu.Unlock()
+
+	e.subscribers.seq++
+	update := PriceUpdate{Seq: e.subscribers.seq, Price: pricecfg, Info: pi}
+	e.subscribers.recent[update.Seq%uint64(len(e.subscribers.recent))] = update
+
+	for ch := range e.subscribers.channels {
+		select {
+		case ch <- update:
+		default:
+			log.WithFields(log.Fields{
+				"buffer": cap(ch),
+			}).Warn("Dropping a subscriber which is too slow to keep up with price updates")
+			e.subscribers.remove(ch)
+		}
+	}
+}
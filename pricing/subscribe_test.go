@@ -0,0 +1,75 @@
+package pricing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"code.vegaprotocol.io/priceproxy/config"
+)
+
+func TestSubscribe(t *testing.T) {
+	price := config.PriceConfig{Source: "a", Base: "BTC", Quote: "USD", Factor: 1}
+	e := NewEngine(config.PriceList{price}).(*engine)
+
+	updates, unsubscribe := e.Subscribe(1)
+	slow, _ := e.Subscribe(1)
+
+	e.UpdatePrice(price, PriceInfo{Price: decimal.NewFromInt(100), LastUpdatedReal: time.Now()})
+	update := <-updates
+	assert.Equal(t, price, update.Price)
+	assert.Equal(t, "100", update.Info.Price.String())
+
+	// the slow subscriber has not read the first update, so it is dropped on the second
+	e.UpdatePrice(price, PriceInfo{Price: decimal.NewFromInt(101), LastUpdatedReal: time.Now()})
+	update = <-updates
+	assert.Equal(t, "101", update.Info.Price.String())
+	<-slow
+	_, ok := <-slow
+	assert.False(t, ok)
+
+	// prices which are not configured are not published
+	e.UpdatePrice(config.PriceConfig{Source: "b", Base: "BTC", Quote: "USD"}, PriceInfo{Price: decimal.NewFromInt(1)})
+	assert.Empty(t, updates)
+
+	unsubscribe()
+	unsubscribe()
+	_, ok = <-updates
+	assert.False(t, ok)
+	require.Empty(t, e.subscribers.channels)
+}
+
+func TestUpdatesSince(t *testing.T) {
+	price := config.PriceConfig{Source: "a", Base: "BTC", Quote: "USD", Factor: 1}
+	e := NewEngine(config.PriceList{price}).(*engine)
+
+	updates, ok := e.UpdatesSince(0)
+	assert.True(t, ok)
+	assert.Empty(t, updates)
+
+	for i := 1; i <= updateReplaySize+10; i++ {
+		e.UpdatePrice(price, PriceInfo{Price: decimal.NewFromInt(int64(i)), LastUpdatedReal: time.Now()})
+	}
+	last := e.LastUpdateSeq()
+	assert.Equal(t, uint64(updateReplaySize+10), last)
+
+	updates, ok = e.UpdatesSince(last - 2)
+	require.True(t, ok)
+	require.Len(t, updates, 2)
+	assert.Equal(t, last-1, updates[0].Seq)
+	assert.Equal(t, last, updates[1].Seq)
+	assert.Equal(t, decimal.NewFromInt(int64(last)).String(), updates[1].Info.Price.String())
+
+	updates, ok = e.UpdatesSince(last - updateReplaySize)
+	assert.True(t, ok)
+	assert.Len(t, updates, updateReplaySize)
+
+	// too old, or not published yet
+	_, ok = e.UpdatesSince(last - updateReplaySize - 1)
+	assert.False(t, ok)
+	_, ok = e.UpdatesSince(last + 1)
+	assert.False(t, ok)
+}
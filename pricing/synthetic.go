@@ -0,0 +1,67 @@
+package pricing
+
+import (
+	"time"
+
+	"github.com/shopspring/decimal"
+
+	"code.vegaprotocol.io/priceproxy/config"
+	"code.vegaprotocol.io/priceproxy/expression"
+)
+
+// synthesize recalculates a synthetic price from the latest real prices it references: never wandered, but as
+// moved by scenarios. It returns false when it cannot be calculated, e.g. until every referenced price is known,
+// in which case the current price is returned. The synthetic price is as old as the oldest price it references.
+// It must be called with pricesMu held.
+func (e *engine) synthesize(pricecfg config.PriceConfig, now time.Time) (PriceInfo, bool) {
+	key := pricecfg.Key()
+	current := e.prices[key]
+	expr, found := e.expressions[key]
+	if !found {
+		return current, false
+	}
+
+	values := make(map[expression.Reference]decimal.Decimal)
+	lastUpdatedReal := time.Time{}
+	scenario := ""
+	for _, ref := range expr.References() {
+		var pi PriceInfo
+		component := config.PriceConfig{Source: ref.Source, Base: ref.Base, Quote: ref.Quote, Factor: 1.0}
+		if dependency, isPrice := e.priceList.Resolve(component); isPrice {
+			dependencyKey := dependency.Key()
+			if _, overridden := e.overrides[dependencyKey]; overridden {
+				pi, found = e.prices[dependencyKey]
+			} else {
+				pi, found = e.realPrices[dependencyKey]
+			}
+			found = found && pi.LastUpdatedReal.Unix() > 0
+		} else {
+			// fetched prices are kept as fetched (or moved by a scenario) for the prices using them, even when
+			// they are published wandered
+			pi, found = e.componentPrices[component.Key()]
+		}
+		if !found {
+			return current, false
+		}
+
+		values[ref] = pi.Price
+		if pi.Scenario != "" {
+			scenario = pi.Scenario
+		}
+		if lastUpdatedReal.IsZero() || pi.LastUpdatedReal.Before(lastUpdatedReal) {
+			lastUpdatedReal = pi.LastUpdatedReal
+		}
+	}
+
+	price, err := expr.Eval(values)
+	if err != nil {
+		return current, false
+	}
+
+	return PriceInfo{
+		Price:             price,
+		LastUpdatedReal:   lastUpdatedReal,
+		LastUpdatedWander: now,
+		Scenario:          scenario,
+	}, true
+}
@@ -0,0 +1,101 @@
+package pricing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"code.vegaprotocol.io/priceproxy/config"
+)
+
+func TestSyntheticPrices(t *testing.T) {
+	btc := config.PriceConfig{Source: "a", Base: "BTC", Quote: "USD", Factor: 1}
+	eth := config.PriceConfig{Source: "a", Base: "ETH", Quote: "USD", Factor: 1}
+	ethB := config.PriceConfig{Source: "b", Base: "ETH", Quote: "USD", Factor: 1}
+	agg := config.PriceConfig{
+		Source:    "agg",
+		Base:      "ETH",
+		Quote:     "USD",
+		Factor:    1,
+		Aggregate: &config.AggregateConfig{MaxAge: 600, Sources: []config.AggregateSource{{Source: "a"}, {Source: "b"}}},
+	}
+	ratio := config.PriceConfig{
+		Source:    "synthetic",
+		Base:      "BTC",
+		Quote:     "ETH",
+		Factor:    1,
+		Synthetic: &config.SyntheticConfig{Expression: "a:BTC/USD / agg:ETH/USD * 0.5 + 10"},
+	}
+	chained := config.PriceConfig{
+		Source:    "chained",
+		Base:      "BTC",
+		Quote:     "ETH",
+		Factor:    1,
+		Synthetic: &config.SyntheticConfig{Expression: "synthetic:BTC/ETH - 10"},
+	}
+	e := NewEngine(config.PriceList{chained, ratio, agg}).(*engine)
+	// prices referenced by their name are not fetched
+	assert.ElementsMatch(t, config.PriceList{btc, eth, ethB}, e.fetchList)
+
+	// not calculated until every referenced price is known
+	e.UpdatePrice(btc, PriceInfo{Price: decimal.NewFromInt(20000), LastUpdatedReal: time.Now().Add(-time.Minute)})
+	pi, err := e.GetPrice(ratio)
+	require.NoError(t, err)
+	assert.True(t, pi.Price.IsZero())
+
+	e.UpdatePrice(eth, PriceInfo{Price: decimal.NewFromInt(900), LastUpdatedReal: time.Now()})
+	e.UpdatePrice(ethB, PriceInfo{Price: decimal.NewFromInt(1100), LastUpdatedReal: time.Now()})
+
+	pi, err = e.GetPrice(ratio)
+	require.NoError(t, err)
+	assert.Equal(t, "20", pi.Price.String())
+	assert.True(t, pi.LastUpdatedReal.Before(time.Now().Add(-50*time.Second)), "as old as the oldest price")
+
+	pi, err = e.GetPrice(chained)
+	require.NoError(t, err)
+	assert.Equal(t, "10", pi.Price.String())
+
+	// recalculated whenever a referenced price changes, through the chain
+	e.UpdatePrice(btc, PriceInfo{Price: decimal.NewFromInt(40000), LastUpdatedReal: time.Now()})
+	pi, err = e.GetPrice(chained)
+	require.NoError(t, err)
+	assert.Equal(t, "20", pi.Price.String())
+
+	// the longest max age of the referenced prices
+	require.NoError(t, e.AddSource(staticSource("a", "1")))
+	assert.Equal(t, 3*time.Second, e.PriceMaxAge(btc))
+	assert.Equal(t, 10*time.Minute, e.PriceMaxAge(chained))
+}
+
+func TestSyntheticPricesIgnoreWander(t *testing.T) {
+	btc := config.PriceConfig{Source: "a", Base: "BTC", Quote: "USD", Factor: 1}
+	wandered := config.PriceConfig{Source: "a", Base: "BTC", Quote: "USD", Factor: 1, Wander: true}
+	double := config.PriceConfig{
+		Source:    "synthetic",
+		Base:      "BTC",
+		Quote:     "USD",
+		Factor:    1,
+		Synthetic: &config.SyntheticConfig{Expression: "a:BTC/USD * 2"},
+	}
+	e := NewEngine(config.PriceList{wandered, double}).(*engine)
+
+	e.UpdatePrice(wandered, PriceInfo{Price: decimal.NewFromInt(100), LastUpdatedReal: time.Now()})
+	e.UpdatePrice(btc, PriceInfo{Price: decimal.NewFromInt(100), LastUpdatedReal: time.Now()})
+	e.wanderPrice(wandered, func(current, real PriceInfo) (PriceInfo, bool) {
+		current.Price = decimal.NewFromInt(105)
+		return current, true
+	})
+
+	pi, err := e.GetPrice(wandered)
+	require.NoError(t, err)
+	assert.Equal(t, "105", pi.Price.String())
+
+	// the synthetic price is recalculated from the real price, not the wandered one
+	e.UpdatePrice(btc, PriceInfo{Price: decimal.NewFromInt(100), LastUpdatedReal: time.Now()})
+	pi, err = e.GetPrice(double)
+	require.NoError(t, err)
+	assert.Equal(t, "200", pi.Price.String())
+}
@@ -0,0 +1,125 @@
+package pricing
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"code.vegaprotocol.io/priceproxy/config"
+)
+
+var (
+	testTracerProvider     *sdktrace.TracerProvider
+	testTracerProviderOnce sync.Once
+)
+
+// recordSpans records the spans ended until the end of the test. The global tracer provider is only set once,
+// as the tracer of the package keeps using the first one.
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+
+	testTracerProviderOnce.Do(func() {
+		testTracerProvider = sdktrace.NewTracerProvider()
+		otel.SetTracerProvider(testTracerProvider)
+	})
+	recorder := tracetest.NewSpanRecorder()
+	testTracerProvider.RegisterSpanProcessor(recorder)
+	t.Cleanup(func() { testTracerProvider.UnregisterSpanProcessor(recorder) })
+	return recorder
+}
+
+// endedSpans returns the ended spans of a trace, by name.
+func endedSpans(recorder *tracetest.SpanRecorder, traceID trace.TraceID) map[string]sdktrace.ReadOnlySpan {
+	result := map[string]sdktrace.ReadOnlySpan{}
+	for _, span := range recorder.Ended() {
+		if span.SpanContext().TraceID() == traceID {
+			result[span.Name()] = span
+		}
+	}
+	return result
+}
+
+func spanAttributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	result := map[attribute.Key]attribute.Value{}
+	for _, kv := range span.Attributes() {
+		result[kv.Key] = kv.Value
+	}
+	return result
+}
+
+func TestFetchJSONSpans(t *testing.T) {
+	recorder := recordSpans(t)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/price", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"price": "1.5"}`))
+	})
+	mux.HandleFunc("/invalid", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"price":`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	client, err := newSourceClient(config.SourceConfig{Name: "test"})
+	require.NoError(t, err)
+
+	// fetch in a parent span, as a fetch cycle does
+	fetch := func(path string) (trace.Span, error) {
+		ctx, parent := tracer.Start(context.Background(), "fetch test")
+		defer parent.End()
+		var doc interface{}
+		return parent, client.fetchJSON(ctx, server.URL+path, &doc)
+	}
+
+	parent, err := fetch("/price?api_key=secret")
+	require.NoError(t, err)
+	spans := endedSpans(recorder, parent.SpanContext().TraceID())
+	require.Contains(t, spans, "HTTP GET")
+	require.Contains(t, spans, "decode")
+
+	get := spans["HTTP GET"]
+	assert.Equal(t, parent.SpanContext().SpanID(), get.Parent().SpanID())
+	assert.Equal(t, trace.SpanKindClient, get.SpanKind())
+	attributes := spanAttributes(get)
+	assert.Equal(t, http.MethodGet, attributes[semconv.HTTPMethodKey].AsString())
+	assert.Equal(t, server.URL+"/price", attributes[semconv.HTTPURLKey].AsString())
+	assert.Equal(t, int64(http.StatusOK), attributes[semconv.HTTPStatusCodeKey].AsInt64())
+	assert.Equal(t, codes.Unset, get.Status().Code)
+
+	decode := spans["decode"]
+	assert.Equal(t, parent.SpanContext().SpanID(), decode.Parent().SpanID())
+	assert.Equal(t, int64(len(`{"price": "1.5"}`)), spanAttributes(decode)["size"].AsInt64())
+	assert.Equal(t, codes.Unset, decode.Status().Code)
+
+	// failures end the span with an error status, and record the error
+	parent, err = fetch("/missing")
+	require.Error(t, err)
+	spans = endedSpans(recorder, parent.SpanContext().TraceID())
+	require.Contains(t, spans, "HTTP GET")
+	assert.NotContains(t, spans, "decode")
+	get = spans["HTTP GET"]
+	assert.Equal(t, int64(http.StatusNotFound), spanAttributes(get)[semconv.HTTPStatusCodeKey].AsInt64())
+	assert.Equal(t, codes.Error, get.Status().Code)
+	assert.Equal(t, err.Error(), get.Status().Description)
+	require.Len(t, get.Events(), 1)
+	assert.Equal(t, "exception", get.Events()[0].Name)
+
+	parent, err = fetch("/invalid")
+	require.Error(t, err)
+	spans = endedSpans(recorder, parent.SpanContext().TraceID())
+	require.Contains(t, spans, "decode")
+	assert.Equal(t, codes.Unset, spans["HTTP GET"].Status().Code)
+	assert.Equal(t, codes.Error, spans["decode"].Status().Code)
+}
@@ -0,0 +1,189 @@
+package pricing
+
+import (
+	"time"
+
+	"code.vegaprotocol.io/priceproxy/config"
+	"github.com/shopspring/decimal"
+	log "github.com/sirupsen/logrus"
+)
+
+// defaultConfirmations is the number of consecutive fetches needed to accept a price jump, when not configured.
+const defaultConfirmations = 3
+
+// RejectReason describes why a fetched price was not published.
+type RejectReason string
+
+const (
+	// RejectReasonZero is used for prices which are zero, typically because the quote was missing upstream.
+	RejectReasonZero RejectReason = "zero"
+
+	// RejectReasonNegative is used for prices below zero.
+	RejectReasonNegative RejectReason = "negative"
+
+	// RejectReasonJump is used for prices which moved too far from the last accepted price, and have not
+	// (yet) been confirmed by enough consecutive fetches.
+	RejectReasonJump RejectReason = "jump"
+)
+
+// Rejections counts the fetched prices which were not published, by reason.
+type Rejections struct {
+	Counts     map[RejectReason]uint64
+	LastReason RejectReason
+	LastPrice  decimal.Decimal
+	LastTime   time.Time
+}
+
+type acceptedPrice struct {
+	price decimal.Decimal
+	at    time.Time
+}
+
+type pendingJump struct {
+	price decimal.Decimal
+	count int
+}
+
+// priceValidator checks fetched prices before they are published. It is not safe for concurrent use.
+type priceValidator struct {
+	accepted   map[config.PriceKey]acceptedPrice
+	pending    map[config.PriceKey]pendingJump
+	rejections map[config.PriceKey]*Rejections
+}
+
+func newPriceValidator() *priceValidator {
+	return &priceValidator{
+		accepted:   make(map[config.PriceKey]acceptedPrice),
+		pending:    make(map[config.PriceKey]pendingJump),
+		rejections: make(map[config.PriceKey]*Rejections),
+	}
+}
+
+// validate returns true if the new price may be published. Otherwise, the rejection is logged and counted.
+func (v *priceValidator) validate(
+	pricecfg config.PriceConfig,
+	validationcfg *config.ValidationConfig,
+	newPrice PriceInfo,
+	now time.Time,
+) bool {
+	key := pricecfg.Key()
+	reason, ok := v.check(key, validationcfg, newPrice.Price, now)
+	if ok {
+		v.accepted[key] = acceptedPrice{price: newPrice.Price, at: now}
+		return true
+	}
+
+	rejections, found := v.rejections[key]
+	if !found {
+		rejections = &Rejections{Counts: map[RejectReason]uint64{}}
+		v.rejections[key] = rejections
+	}
+	rejections.Counts[reason]++
+	rejections.LastReason = reason
+	rejections.LastPrice = newPrice.Price
+	rejections.LastTime = now
+
+	log.WithFields(log.Fields{
+		"sourceName":     pricecfg.Source,
+		"base":           pricecfg.Base,
+		"quote":          pricecfg.Quote,
+		"quote_override": pricecfg.QuoteOverride,
+		"price":          newPrice.Price,
+		"lastAccepted":   v.accepted[key].price,
+		"reason":         reason,
+		"rejectedCount":  rejections.Counts[reason],
+	}).Warnln("Rejected fetched price")
+
+	return false
+}
+
+func (v *priceValidator) check(
+	key config.PriceKey,
+	validationcfg *config.ValidationConfig,
+	price decimal.Decimal,
+	now time.Time,
+) (RejectReason, bool) {
+	if price.IsZero() {
+		return RejectReasonZero, false
+	}
+	if price.IsNegative() {
+		return RejectReasonNegative, false
+	}
+
+	if validationcfg == nil || validationcfg.MaxJump <= 0 {
+		return "", true
+	}
+
+	last, found := v.accepted[key]
+	window := time.Duration(validationcfg.JumpWindow) * time.Second
+	if !found || (window > 0 && now.Sub(last.at) > window) {
+		delete(v.pending, key)
+		return "", true
+	}
+
+	if !isJump(last.price, price, validationcfg.MaxJump) {
+		delete(v.pending, key)
+		return "", true
+	}
+
+	// the price jumped: accept it only once enough consecutive fetches agree on the new level
+	pending, found := v.pending[key]
+	if found && !isJump(pending.price, price, validationcfg.MaxJump) {
+		pending.count++
+	} else {
+		pending = pendingJump{count: 1}
+	}
+	pending.price = price
+
+	confirmations := validationcfg.Confirmations
+	if confirmations == 0 {
+		confirmations = defaultConfirmations
+	}
+	if pending.count >= confirmations {
+		delete(v.pending, key)
+		return "", true
+	}
+
+	v.pending[key] = pending
+	return RejectReasonJump, false
+}
+
+// getRejections returns a copy of the rejection counts of a price.
+func (v *priceValidator) getRejections(pricecfg config.PriceConfig) Rejections {
+	rejections, found := v.rejections[pricecfg.Key()]
+	if !found {
+		return Rejections{Counts: map[RejectReason]uint64{}}
+	}
+
+	result := *rejections
+	result.Counts = make(map[RejectReason]uint64, len(rejections.Counts))
+	for reason, count := range rejections.Counts {
+		result.Counts[reason] = count
+	}
+	return result
+}
+
+// isJump returns true if price is more than maxJump percent away from reference.
+func isJump(reference, price decimal.Decimal, maxJump float64) bool {
+	move := price.Sub(reference).Abs().Mul(decimal.NewFromInt(100))
+	return move.GreaterThan(reference.Mul(decimal.NewFromFloat(maxJump)))
+}
+
+// forget drops the state kept for the prices matching drop, e.g. prices removed from the config.
+func (v *priceValidator) forget(drop func(key config.PriceKey) bool) {
+	for key := range v.accepted {
+		if drop(key) {
+			delete(v.accepted, key)
+		}
+	}
+	for key := range v.pending {
+		if drop(key) {
+			delete(v.pending, key)
+		}
+	}
+	for key := range v.rejections {
+		if drop(key) {
+			delete(v.rejections, key)
+		}
+	}
+}
@@ -0,0 +1,44 @@
+package pricing
+
+import (
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+
+	"code.vegaprotocol.io/priceproxy/config"
+)
+
+func TestPriceValidator(t *testing.T) {
+	pricecfg := config.PriceConfig{Source: "src", Base: "BTC", Quote: "USD", Factor: 1}
+	validationcfg := &config.ValidationConfig{MaxJump: 10, JumpWindow: 60, Confirmations: 2}
+	now := time.Now()
+	v := newPriceValidator()
+
+	assert.False(t, v.validate(pricecfg, validationcfg, PriceInfo{Price: decimal.NewFromInt(0)}, now))
+	assert.False(t, v.validate(pricecfg, validationcfg, PriceInfo{Price: decimal.NewFromInt(-1)}, now))
+	assert.True(t, v.validate(pricecfg, validationcfg, PriceInfo{Price: decimal.NewFromInt(100)}, now))
+	assert.True(t, v.validate(pricecfg, validationcfg, PriceInfo{Price: decimal.NewFromInt(109)}, now))
+
+	// a jump is rejected until confirmed by consecutive fetches
+	assert.False(t, v.validate(pricecfg, validationcfg, PriceInfo{Price: decimal.NewFromInt(150)}, now))
+	assert.True(t, v.validate(pricecfg, validationcfg, PriceInfo{Price: decimal.NewFromInt(151)}, now))
+
+	// an unconfirmed jump is forgotten
+	assert.False(t, v.validate(pricecfg, validationcfg, PriceInfo{Price: decimal.NewFromInt(50)}, now))
+	assert.True(t, v.validate(pricecfg, validationcfg, PriceInfo{Price: decimal.NewFromInt(152)}, now))
+	assert.False(t, v.validate(pricecfg, validationcfg, PriceInfo{Price: decimal.NewFromInt(50)}, now))
+
+	// outside the window, anything goes
+	assert.True(t, v.validate(pricecfg, validationcfg, PriceInfo{Price: decimal.NewFromInt(50)}, now.Add(2*time.Minute)))
+
+	rejections := v.getRejections(pricecfg)
+	assert.Equal(t, uint64(1), rejections.Counts[RejectReasonZero])
+	assert.Equal(t, uint64(1), rejections.Counts[RejectReasonNegative])
+	assert.Equal(t, uint64(3), rejections.Counts[RejectReasonJump])
+	assert.Equal(t, RejectReasonJump, rejections.LastReason)
+
+	// without validation config, only zero and negative prices are rejected
+	assert.True(t, v.validate(pricecfg, nil, PriceInfo{Price: decimal.NewFromInt(5000)}, now))
+}
@@ -0,0 +1,87 @@
+package pricing
+
+import (
+	"context"
+	"math/rand"
+	"time"
+
+	"code.vegaprotocol.io/priceproxy/config"
+	"github.com/shopspring/decimal"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// wanderStep is the largest relative move a price makes in one wander step.
+	wanderStep = 0.001
+
+	// wanderMaxDeviation is the largest relative distance a wandered price may drift from the last real price.
+	wanderMaxDeviation = 0.01
+)
+
+type wanderBoard interface {
+	PriceList(source string) config.PriceList
+	wanderPrice(pricecfg config.PriceConfig, wander func(current, real PriceInfo) (PriceInfo, bool))
+}
+
+func wanderStart(
+	ctx context.Context,
+	board wanderBoard,
+	sourcecfg config.SourceConfig,
+) {
+	oneStepEvery := time.Duration(sourcecfg.SleepWander) * time.Second
+
+	log.WithFields(log.Fields{
+		"sourceName":   sourcecfg.Name,
+		"wanderPeriod": oneStepEvery,
+	}).Infof("Starting price wandering\n")
+
+	ticker := time.NewTicker(oneStepEvery)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		for _, price := range board.PriceList(sourcecfg.Name) {
+			if !price.Wander {
+				continue
+			}
+
+			board.wanderPrice(price, func(current, real PriceInfo) (PriceInfo, bool) {
+				if !real.Price.IsPositive() {
+					// nothing real to wander around yet
+					return PriceInfo{}, false
+				}
+
+				next := current
+				next.Price = wanderNextPrice(current.Price, real.Price, rand.Float64()) // nolint:gosec
+				next.LastUpdatedWander = time.Now().Round(0)
+				return next, true
+			})
+		}
+	}
+}
+
+// wanderNextPrice moves the current price by at most wanderStep (scaled by r, which is in [0, 1)),
+// keeping the result within wanderMaxDeviation of the real price.
+func wanderNextPrice(current, real decimal.Decimal, r float64) decimal.Decimal {
+	if !current.IsPositive() {
+		current = real
+	}
+
+	next := current.Mul(decimal.NewFromFloat(1 + (2*r-1)*wanderStep)).Round(divisionPrecision)
+
+	lower := real.Mul(decimal.NewFromFloat(1 - wanderMaxDeviation))
+	upper := real.Mul(decimal.NewFromFloat(1 + wanderMaxDeviation))
+	if next.LessThan(lower) {
+		return lower
+	}
+	if next.GreaterThan(upper) {
+		return upper
+	}
+
+	return next
+}
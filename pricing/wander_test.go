@@ -0,0 +1,44 @@
+package pricing
+
+import (
+	"testing"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWanderNextPrice(t *testing.T) {
+	tests := []struct {
+		name     string
+		current  string
+		real     string
+		r        float64
+		expected string
+	}{
+		{name: "no move", current: "100", real: "100", r: 0.5, expected: "100"},
+		{name: "down one step", current: "100", real: "100", r: 0, expected: "99.9"},
+		{name: "up half a step", current: "100", real: "100", r: 0.75, expected: "100.05"},
+		{name: "no current price", current: "0", real: "100", r: 0.75, expected: "100.05"},
+		{name: "capped at the top of the band", current: "100.95", real: "100", r: 0.99, expected: "101"},
+		{name: "capped at the bottom of the band", current: "99.05", real: "100", r: 0, expected: "99"},
+		{name: "back into the band from below", current: "50", real: "100", r: 0.5, expected: "99"},
+		{name: "back into the band from above", current: "200", real: "100", r: 0.5, expected: "101"},
+		{name: "zero real and current prices", current: "0", real: "0", r: 0.75, expected: "0"},
+		{name: "zero real price", current: "100", real: "0", r: 0.75, expected: "0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			next := wanderNextPrice(decimal.RequireFromString(tt.current), decimal.RequireFromString(tt.real), tt.r)
+			assert.Equal(t, tt.expected, next.String())
+		})
+	}
+
+	// whatever r, the price stays within the band around the real price
+	real := decimal.NewFromInt(100)
+	current := real
+	for i := 0; i < 1000; i++ {
+		current = wanderNextPrice(current, real, float64(i%10)/10)
+		assert.True(t, current.GreaterThanOrEqual(decimal.NewFromInt(99)), current.String())
+		assert.True(t, current.LessThanOrEqual(decimal.NewFromInt(101)), current.String())
+	}
+}
@@ -0,0 +1,6 @@
+// Package priceproxyv1 has the gRPC API of the price proxy, generated from priceproxy.proto.
+//
+// Regenerate it with protoc, protoc-gen-go v1.30.0 and protoc-gen-go-grpc v1.3.0 installed.
+//
+//go:generate protoc -I ../.. --go_out=../.. --go_opt=paths=source_relative --go-grpc_out=../.. --go-grpc_opt=paths=source_relative priceproxy/v1/priceproxy.proto
+package priceproxyv1
@@ -0,0 +1,297 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.3.0
+// - protoc             (unknown)
+// source: priceproxy/v1/priceproxy.proto
+
+package priceproxyv1
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.32.0 or later.
+const _ = grpc.SupportPackageIsVersion7
+
+const (
+	PriceProxyService_ListPrices_FullMethodName  = "/priceproxy.v1.PriceProxyService/ListPrices"
+	PriceProxyService_GetPrice_FullMethodName    = "/priceproxy.v1.PriceProxyService/GetPrice"
+	PriceProxyService_ListSources_FullMethodName = "/priceproxy.v1.PriceProxyService/ListSources"
+	PriceProxyService_GetSource_FullMethodName   = "/priceproxy.v1.PriceProxyService/GetSource"
+	PriceProxyService_WatchPrices_FullMethodName = "/priceproxy.v1.PriceProxyService/WatchPrices"
+)
+
+// PriceProxyServiceClient is the client API for PriceProxyService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type PriceProxyServiceClient interface {
+	// ListPrices lists the prices matching a filter, as GET /prices.
+	ListPrices(ctx context.Context, in *ListPricesRequest, opts ...grpc.CallOption) (*ListPricesResponse, error)
+	// GetPrice gets one price. It fails with INVALID_ARGUMENT if several prices match.
+	GetPrice(ctx context.Context, in *GetPriceRequest, opts ...grpc.CallOption) (*GetPriceResponse, error)
+	// ListSources lists all sources, as GET /sources.
+	ListSources(ctx context.Context, in *ListSourcesRequest, opts ...grpc.CallOption) (*ListSourcesResponse, error)
+	// GetSource gets one source, as GET /sources/{name}.
+	GetSource(ctx context.Context, in *GetSourceRequest, opts ...grpc.CallOption) (*GetSourceResponse, error)
+	// WatchPrices sends a snapshot of the prices matching a filter, then an update whenever one of them
+	// changes, as /ws/prices.
+	WatchPrices(ctx context.Context, in *WatchPricesRequest, opts ...grpc.CallOption) (PriceProxyService_WatchPricesClient, error)
+}
+
+type priceProxyServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPriceProxyServiceClient(cc grpc.ClientConnInterface) PriceProxyServiceClient {
+	return &priceProxyServiceClient{cc}
+}
+
+func (c *priceProxyServiceClient) ListPrices(ctx context.Context, in *ListPricesRequest, opts ...grpc.CallOption) (*ListPricesResponse, error) {
+	out := new(ListPricesResponse)
+	err := c.cc.Invoke(ctx, PriceProxyService_ListPrices_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *priceProxyServiceClient) GetPrice(ctx context.Context, in *GetPriceRequest, opts ...grpc.CallOption) (*GetPriceResponse, error) {
+	out := new(GetPriceResponse)
+	err := c.cc.Invoke(ctx, PriceProxyService_GetPrice_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *priceProxyServiceClient) ListSources(ctx context.Context, in *ListSourcesRequest, opts ...grpc.CallOption) (*ListSourcesResponse, error) {
+	out := new(ListSourcesResponse)
+	err := c.cc.Invoke(ctx, PriceProxyService_ListSources_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *priceProxyServiceClient) GetSource(ctx context.Context, in *GetSourceRequest, opts ...grpc.CallOption) (*GetSourceResponse, error) {
+	out := new(GetSourceResponse)
+	err := c.cc.Invoke(ctx, PriceProxyService_GetSource_FullMethodName, in, out, opts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *priceProxyServiceClient) WatchPrices(ctx context.Context, in *WatchPricesRequest, opts ...grpc.CallOption) (PriceProxyService_WatchPricesClient, error) {
+	stream, err := c.cc.NewStream(ctx, &PriceProxyService_ServiceDesc.Streams[0], PriceProxyService_WatchPrices_FullMethodName, opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &priceProxyServiceWatchPricesClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type PriceProxyService_WatchPricesClient interface {
+	Recv() (*WatchPricesResponse, error)
+	grpc.ClientStream
+}
+
+type priceProxyServiceWatchPricesClient struct {
+	grpc.ClientStream
+}
+
+func (x *priceProxyServiceWatchPricesClient) Recv() (*WatchPricesResponse, error) {
+	m := new(WatchPricesResponse)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// PriceProxyServiceServer is the server API for PriceProxyService service.
+// All implementations must embed UnimplementedPriceProxyServiceServer
+// for forward compatibility
+type PriceProxyServiceServer interface {
+	// ListPrices lists the prices matching a filter, as GET /prices.
+	ListPrices(context.Context, *ListPricesRequest) (*ListPricesResponse, error)
+	// GetPrice gets one price. It fails with INVALID_ARGUMENT if several prices match.
+	GetPrice(context.Context, *GetPriceRequest) (*GetPriceResponse, error)
+	// ListSources lists all sources, as GET /sources.
+	ListSources(context.Context, *ListSourcesRequest) (*ListSourcesResponse, error)
+	// GetSource gets one source, as GET /sources/{name}.
+	GetSource(context.Context, *GetSourceRequest) (*GetSourceResponse, error)
+	// WatchPrices sends a snapshot of the prices matching a filter, then an update whenever one of them
+	// changes, as /ws/prices.
+	WatchPrices(*WatchPricesRequest, PriceProxyService_WatchPricesServer) error
+	mustEmbedUnimplementedPriceProxyServiceServer()
+}
+
+// UnimplementedPriceProxyServiceServer must be embedded to have forward compatible implementations.
+type UnimplementedPriceProxyServiceServer struct {
+}
+
+func (UnimplementedPriceProxyServiceServer) ListPrices(context.Context, *ListPricesRequest) (*ListPricesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListPrices not implemented")
+}
+func (UnimplementedPriceProxyServiceServer) GetPrice(context.Context, *GetPriceRequest) (*GetPriceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPrice not implemented")
+}
+func (UnimplementedPriceProxyServiceServer) ListSources(context.Context, *ListSourcesRequest) (*ListSourcesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListSources not implemented")
+}
+func (UnimplementedPriceProxyServiceServer) GetSource(context.Context, *GetSourceRequest) (*GetSourceResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetSource not implemented")
+}
+func (UnimplementedPriceProxyServiceServer) WatchPrices(*WatchPricesRequest, PriceProxyService_WatchPricesServer) error {
+	return status.Errorf(codes.Unimplemented, "method WatchPrices not implemented")
+}
+func (UnimplementedPriceProxyServiceServer) mustEmbedUnimplementedPriceProxyServiceServer() {}
+
+// UnsafePriceProxyServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PriceProxyServiceServer will
+// result in compilation errors.
+type UnsafePriceProxyServiceServer interface {
+	mustEmbedUnimplementedPriceProxyServiceServer()
+}
+
+func RegisterPriceProxyServiceServer(s grpc.ServiceRegistrar, srv PriceProxyServiceServer) {
+	s.RegisterService(&PriceProxyService_ServiceDesc, srv)
+}
+
+func _PriceProxyService_ListPrices_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListPricesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PriceProxyServiceServer).ListPrices(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PriceProxyService_ListPrices_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PriceProxyServiceServer).ListPrices(ctx, req.(*ListPricesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PriceProxyService_GetPrice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPriceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PriceProxyServiceServer).GetPrice(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PriceProxyService_GetPrice_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PriceProxyServiceServer).GetPrice(ctx, req.(*GetPriceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PriceProxyService_ListSources_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListSourcesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PriceProxyServiceServer).ListSources(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PriceProxyService_ListSources_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PriceProxyServiceServer).ListSources(ctx, req.(*ListSourcesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PriceProxyService_GetSource_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetSourceRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PriceProxyServiceServer).GetSource(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PriceProxyService_GetSource_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PriceProxyServiceServer).GetSource(ctx, req.(*GetSourceRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _PriceProxyService_WatchPrices_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(WatchPricesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PriceProxyServiceServer).WatchPrices(m, &priceProxyServiceWatchPricesServer{stream})
+}
+
+type PriceProxyService_WatchPricesServer interface {
+	Send(*WatchPricesResponse) error
+	grpc.ServerStream
+}
+
+type priceProxyServiceWatchPricesServer struct {
+	grpc.ServerStream
+}
+
+func (x *priceProxyServiceWatchPricesServer) Send(m *WatchPricesResponse) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+// PriceProxyService_ServiceDesc is the grpc.ServiceDesc for PriceProxyService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var PriceProxyService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "priceproxy.v1.PriceProxyService",
+	HandlerType: (*PriceProxyServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListPrices",
+			Handler:    _PriceProxyService_ListPrices_Handler,
+		},
+		{
+			MethodName: "GetPrice",
+			Handler:    _PriceProxyService_GetPrice_Handler,
+		},
+		{
+			MethodName: "ListSources",
+			Handler:    _PriceProxyService_ListSources_Handler,
+		},
+		{
+			MethodName: "GetSource",
+			Handler:    _PriceProxyService_GetSource_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "WatchPrices",
+			Handler:       _PriceProxyService_WatchPrices_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "priceproxy/v1/priceproxy.proto",
+}
@@ -0,0 +1,301 @@
+package service
+
+import (
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"reflect"
+	"strings"
+
+	"github.com/julienschmidt/httprouter"
+	log "github.com/sirupsen/logrus"
+	"gopkg.in/yaml.v2"
+
+	"code.vegaprotocol.io/priceproxy/config"
+)
+
+// maxAdminBodySize is the largest request body accepted by the admin API.
+const maxAdminBodySize = 1 << 20
+
+// PausedSourcesResponse lists the sources which are paused.
+type PausedSourcesResponse struct {
+	Paused []string `json:"paused"`
+}
+
+// DeletedPricesResponse gives the prices removed by a DELETE /admin/prices request.
+type DeletedPricesResponse struct {
+	Deleted config.PriceList `json:"deleted"`
+}
+
+// statusError is an error which is returned to the client with the given HTTP status.
+type statusError struct {
+	status int
+	err    error
+}
+
+func (e *statusError) Error() string {
+	return e.err.Error()
+}
+
+func newStatusError(status int, format string, a ...interface{}) error {
+	return &statusError{status: status, err: fmt.Errorf(format, a...)}
+}
+
+func (s *Service) addAdminRoutes() {
+	s.adminToken = os.Getenv(s.config.Admin.TokenEnvName)
+	if s.adminToken == "" {
+		log.WithFields(log.Fields{
+			"envName": s.config.Admin.TokenEnvName,
+		}).Warn("The admin token is empty, the admin API is disabled. Export the environment variable set in `admin.token_env_name`")
+		return
+	}
+
+	s.POST("/admin/sources", s.instrument("AdminSourcesPost", s.requireAdmin(s.AdminSourcesPost)))
+	s.DELETE("/admin/sources/:name", s.instrument("AdminSourceDelete", s.requireAdmin(s.AdminSourceDelete)))
+	s.POST("/admin/sources/:name/pause", s.instrument("AdminSourcePause", s.requireAdmin(s.AdminSourcePause)))
+	s.POST("/admin/sources/:name/resume", s.instrument("AdminSourceResume", s.requireAdmin(s.AdminSourceResume)))
+	s.POST("/admin/prices", s.instrument("AdminPricesPost", s.requireAdmin(s.AdminPricesPost)))
+	s.DELETE("/admin/prices", s.instrument("AdminPricesDelete", s.requireAdmin(s.AdminPricesDelete)))
+	s.POST("/admin/scenarios/:name/start", s.instrument("AdminScenarioStart", s.requireAdmin(s.AdminScenarioStart)))
+	s.POST("/admin/scenarios/:name/stop", s.instrument("AdminScenarioStop", s.requireAdmin(s.AdminScenarioStop)))
+}
+
+// requireAdmin only lets through requests with the admin token as bearer token.
+func (s *Service) requireAdmin(handle httprouter.Handle) httprouter.Handle {
+	return func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		authorization := r.Header.Get("Authorization")
+		token := strings.TrimPrefix(authorization, "Bearer ")
+		if !strings.HasPrefix(authorization, "Bearer ") || subtle.ConstantTimeCompare([]byte(token), []byte(s.adminToken)) != 1 {
+			log.WithFields(log.Fields{
+				"method":     r.Method,
+				"path":       r.URL.Path,
+				"remoteAddr": r.RemoteAddr,
+			}).Warn("Unauthorized admin request")
+			writeError(w, errors.New("unauthorized"), http.StatusUnauthorized)
+			return
+		}
+		handle(w, r, ps)
+	}
+}
+
+// updateConfig applies change to a copy of the current config, checks the result, applies it to the running
+// engine and, if enabled, writes it back to the config file.
+func (s *Service) updateConfig(change func(cfg *config.Config) error) error {
+	s.configMu.Lock()
+	defer s.configMu.Unlock()
+
+	cfg := s.config
+	cfg.Prices = append(config.PriceList{}, s.config.Prices...)
+	cfg.Sources = append([]*config.SourceConfig{}, s.config.Sources...)
+
+	if err := change(&cfg); err != nil {
+		return err
+	}
+	if err := config.CheckConfig(&cfg); err != nil {
+		return &statusError{status: http.StatusBadRequest, err: err}
+	}
+	if err := s.reload(cfg); err != nil {
+		return &statusError{status: http.StatusBadRequest, err: err}
+	}
+
+	if s.config.Admin.WriteConfig && s.configFile != "" {
+		if err := config.SaveConfig(s.configFile, cfg); err != nil {
+			return fmt.Errorf("change applied, but not saved: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func writeAdminError(w http.ResponseWriter, err error) {
+	var statusErr *statusError
+	if errors.As(err, &statusErr) {
+		writeError(w, err, statusErr.status)
+		return
+	}
+	writeError(w, err, http.StatusInternalServerError)
+}
+
+// readYAMLBody decodes a request body given as YAML or JSON, using the same field names as the config file.
+func readYAMLBody(r *http.Request, v interface{}) error {
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxAdminBodySize))
+	if err != nil {
+		return newStatusError(http.StatusBadRequest, "failed to read request body: %s", err.Error())
+	}
+	if err := yaml.UnmarshalStrict(body, v); err != nil {
+		return newStatusError(http.StatusBadRequest, "failed to parse request body: %s", err.Error())
+	}
+	return nil
+}
+
+// AdminSourcesPost adds a source.
+func (s *Service) AdminSourcesPost(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	var sourcecfg config.SourceConfig
+	if err := readYAMLBody(r, &sourcecfg); err != nil {
+		writeAdminError(w, err)
+		return
+	}
+
+	err := s.updateConfig(func(cfg *config.Config) error {
+		if sourcecfg.Name == "" {
+			return newStatusError(http.StatusBadRequest, "%s: name", config.ErrMissingEmptyConfigSection.Error())
+		}
+		for _, existing := range cfg.Sources {
+			if existing.Name == sourcecfg.Name {
+				return newStatusError(http.StatusConflict, "source already exists: %s", sourcecfg.Name)
+			}
+		}
+		cfg.Sources = append(cfg.Sources, &sourcecfg)
+		return nil
+	})
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+
+	log.WithContext(r.Context()).WithFields(log.Fields{
+		"name": sourcecfg.Name,
+	}).Info("Added source through the admin API")
+	writeSuccess(w, sourcecfg, http.StatusCreated)
+}
+
+// AdminSourceDelete removes a source. Prices using it must be removed first.
+func (s *Service) AdminSourceDelete(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	name := ps.ByName("name")
+
+	err := s.updateConfig(func(cfg *config.Config) error {
+		for _, pricecfg := range cfg.Prices {
+			if pricecfg.Source == name {
+				return newStatusError(http.StatusConflict, "source is used by price %s/%s", pricecfg.Base, pricecfg.Quote)
+			}
+			for _, component := range pricecfg.Components() {
+				if component.Source == name {
+					return newStatusError(http.StatusConflict, "source is used by aggregated, derived or synthetic price %s/%s", pricecfg.Base, pricecfg.Quote)
+				}
+			}
+		}
+		for i, sourcecfg := range cfg.Sources {
+			if sourcecfg.Name == name {
+				cfg.Sources = append(cfg.Sources[:i], cfg.Sources[i+1:]...)
+				return nil
+			}
+		}
+		return newStatusError(http.StatusNotFound, "price source not found: %s", name)
+	})
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+
+	log.WithContext(r.Context()).WithFields(log.Fields{
+		"name": name,
+	}).Info("Removed source through the admin API")
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// AdminSourcePause stops fetching a source until it is resumed.
+func (s *Service) AdminSourcePause(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	s.pauseResume(w, ps.ByName("name"), s.pe.PauseSource)
+}
+
+// AdminSourceResume starts fetching a paused source again.
+func (s *Service) AdminSourceResume(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	s.pauseResume(w, ps.ByName("name"), s.pe.ResumeSource)
+}
+
+func (s *Service) pauseResume(w http.ResponseWriter, name string, action func(name string) error) {
+	if _, err := s.pe.GetSource(name); err != nil {
+		writeError(w, err, http.StatusNotFound)
+		return
+	}
+	if err := action(name); err != nil {
+		writeError(w, err, http.StatusConflict)
+		return
+	}
+
+	writeSuccess(w, PausedSourcesResponse{Paused: s.pe.PausedSources()}, http.StatusOK)
+}
+
+// AdminPricesPost adds a price.
+func (s *Service) AdminPricesPost(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	var pricecfg config.PriceConfig
+	if err := readYAMLBody(r, &pricecfg); err != nil {
+		writeAdminError(w, err)
+		return
+	}
+
+	err := s.updateConfig(func(cfg *config.Config) error {
+		if pricecfg.Fetched() && !config.HasSource(cfg.Sources, pricecfg.Source) {
+			return newStatusError(http.StatusBadRequest, "price source not found: %s", pricecfg.Source)
+		}
+		for _, existing := range cfg.Prices {
+			if reflect.DeepEqual(existing, pricecfg) {
+				return newStatusError(http.StatusConflict, "price already exists: %s", pricecfg.String())
+			}
+		}
+		cfg.Prices = append(cfg.Prices, pricecfg)
+		return nil
+	})
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+
+	log.WithContext(r.Context()).WithFields(log.Fields{
+		"source": pricecfg.Source,
+		"base":   pricecfg.Base,
+		"quote":  pricecfg.Quote,
+	}).Info("Added price through the admin API")
+	writeSuccess(w, pricecfg, http.StatusCreated)
+}
+
+// AdminPricesDelete removes the prices matching the source, base and quote query parameters, and the
+// base_override and quote_override ones if given.
+func (s *Service) AdminPricesDelete(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	query := r.URL.Query()
+	source, base, quote := query.Get("source"), query.Get("base"), query.Get("quote")
+	if source == "" || base == "" || quote == "" {
+		writeError(w, errors.New("source, base and quote are required"), http.StatusBadRequest)
+		return
+	}
+
+	matches := func(pricecfg config.PriceConfig) bool {
+		return pricecfg.Source == source &&
+			strings.EqualFold(pricecfg.Base, base) &&
+			strings.EqualFold(pricecfg.Quote, quote) &&
+			(!query.Has("base_override") || pricecfg.BaseOverride == query.Get("base_override")) &&
+			(!query.Has("quote_override") || pricecfg.QuoteOverride == query.Get("quote_override"))
+	}
+
+	deleted := config.PriceList{}
+	err := s.updateConfig(func(cfg *config.Config) error {
+		kept := config.PriceList{}
+		for _, pricecfg := range cfg.Prices {
+			if matches(pricecfg) {
+				deleted = append(deleted, pricecfg)
+			} else {
+				kept = append(kept, pricecfg)
+			}
+		}
+		if len(deleted) == 0 {
+			return newStatusError(http.StatusNotFound, "price not found: %s %s/%s", source, base, quote)
+		}
+		cfg.Prices = kept
+		return nil
+	})
+	if err != nil {
+		writeAdminError(w, err)
+		return
+	}
+
+	log.WithContext(r.Context()).WithFields(log.Fields{
+		"source":  source,
+		"base":    base,
+		"quote":   quote,
+		"deleted": len(deleted),
+	}).Info("Removed prices through the admin API")
+	writeSuccess(w, DeletedPricesResponse{Deleted: deleted}, http.StatusOK)
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"code.vegaprotocol.io/priceproxy/config"
+)
+
+const testAdminToken = "secret"
+
+// newTestService starts a service with the admin API enabled, fetching every price at 100 from a local HTTP/JSON
+// source named "a", and waits until the prices are fetched.
+func newTestService(t *testing.T, prices config.PriceList) *Service {
+	t.Helper()
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"price": 100}`))
+	}))
+	t.Cleanup(upstream.Close)
+
+	t.Setenv("PRICEPROXY_TEST_ADMIN_TOKEN", testAdminToken)
+	cfg := config.Config{
+		Server:  &config.ServerConfig{Listen: ":0"},
+		Admin:   &config.AdminConfig{TokenEnvName: "PRICEPROXY_TEST_ADMIN_TOKEN"},
+		Sources: []*config.SourceConfig{testSource(t, "a", upstream.URL)},
+		Prices:  prices,
+	}
+	s, err := NewService(context.Background(), cfg)
+	require.NoError(t, err)
+	t.Cleanup(s.Stop)
+
+	require.Eventually(t, func() bool {
+		for _, pi := range s.pe.GetPrices() {
+			if pi.Price.IsZero() {
+				return false
+			}
+		}
+		return true
+	}, 5*time.Second, 10*time.Millisecond)
+	return s
+}
+
+func testSource(t *testing.T, name, rawURL string) *config.SourceConfig {
+	u, err := url.Parse(rawURL)
+	require.NoError(t, err)
+	return &config.SourceConfig{Name: name, Type: config.SourceTypeHTTP, SleepReal: 600, URL: *u, PricePath: "price", Batch: true}
+}
+
+// serve sends a request to the service, with the given Authorization header if not empty.
+func serve(s *Service, method, target, authorization, body string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(method, target, strings.NewReader(body))
+	if authorization != "" {
+		r.Header.Set("Authorization", authorization)
+	}
+	w := httptest.NewRecorder()
+	s.ServeHTTP(w, r)
+	return w
+}
+
+func TestAdminAuth(t *testing.T) {
+	s := newTestService(t, config.PriceList{{Source: "a", Base: "BTC", Quote: "USD", Factor: 1}})
+
+	for name, authorization := range map[string]string{
+		"missing":    "",
+		"wrong":      "Bearer wrong",
+		"bare token": testAdminToken,
+		"basic":      "Basic " + testAdminToken,
+	} {
+		t.Run(name, func(t *testing.T) {
+			w := serve(s, http.MethodPost, "/admin/sources/a/pause", authorization, "")
+			assert.Equal(t, http.StatusUnauthorized, w.Code)
+		})
+	}
+
+	w := serve(s, http.MethodPost, "/admin/sources/a/pause", "Bearer "+testAdminToken, "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"paused": ["a"]}`, w.Body.String())
+}
+
+func TestAdminSources(t *testing.T) {
+	s := newTestService(t, config.PriceList{{Source: "a", Base: "BTC", Quote: "USD", Factor: 1}})
+	authorization := "Bearer " + testAdminToken
+
+	body := `{"name": "b", "type": "http", "sleepReal": 600, "url": {"scheme": "http", "host": "localhost", "path": "/prices"}, "price_path": "price"}`
+	w := serve(s, http.MethodPost, "/admin/sources", authorization, body)
+	assert.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	_, err := s.pe.GetSource("b")
+	assert.NoError(t, err)
+
+	w = serve(s, http.MethodPost, "/admin/sources", authorization, body)
+	assert.Equal(t, http.StatusConflict, w.Code, w.Body.String())
+
+	w = serve(s, http.MethodPost, "/admin/sources", authorization, `{"type": "http"`)
+	assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+
+	// a source still used by a price cannot be removed
+	w = serve(s, http.MethodDelete, "/admin/sources/a", authorization, "")
+	assert.Equal(t, http.StatusConflict, w.Code, w.Body.String())
+
+	w = serve(s, http.MethodDelete, "/admin/sources/b", authorization, "")
+	assert.Equal(t, http.StatusNoContent, w.Code, w.Body.String())
+	_, err = s.pe.GetSource("b")
+	assert.Error(t, err)
+
+	w = serve(s, http.MethodDelete, "/admin/sources/b", authorization, "")
+	assert.Equal(t, http.StatusNotFound, w.Code, w.Body.String())
+}
+
+func TestAdminPrices(t *testing.T) {
+	s := newTestService(t, config.PriceList{{Source: "a", Base: "BTC", Quote: "USD", Factor: 1}})
+	authorization := "Bearer " + testAdminToken
+
+	w := serve(s, http.MethodPost, "/admin/prices", authorization, `{"source": "a", "base": "ETH", "quote": "USD", "factor": 1.0}`)
+	assert.Equal(t, http.StatusCreated, w.Code, w.Body.String())
+	assert.Len(t, s.pe.GetPrices(), 2)
+
+	w = serve(s, http.MethodDelete, "/admin/prices?source=a&base=DOGE&quote=USD", authorization, "")
+	assert.Equal(t, http.StatusNotFound, w.Code, w.Body.String())
+
+	w = serve(s, http.MethodDelete, "/admin/prices?source=a&base=ETH", authorization, "")
+	assert.Equal(t, http.StatusBadRequest, w.Code, w.Body.String())
+
+	w = serve(s, http.MethodDelete, "/admin/prices?source=a&base=eth&quote=usd", authorization, "")
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Len(t, s.pe.GetPrices(), 1)
+}
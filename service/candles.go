@@ -0,0 +1,139 @@
+package service
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/shopspring/decimal"
+	log "github.com/sirupsen/logrus"
+)
+
+// CandleResponse gives the open, high, low and close of a price over one interval.
+type CandleResponse struct {
+	Time     string          `json:"time"`
+	Open     decimal.Decimal `json:"open"`
+	High     decimal.Decimal `json:"high"`
+	Low      decimal.Decimal `json:"low"`
+	Close    decimal.Decimal `json:"close"`
+	Updates  int             `json:"updates"`
+	Wandered int             `json:"wandered"`
+	Scenario int             `json:"scenario"`
+}
+
+// PriceCandlesResponse gives the candles of one price.
+type PriceCandlesResponse struct {
+	Source    string            `json:"source"`
+	Base      string            `json:"base"`
+	BaseReal  string            `json:"base_real"`
+	Quote     string            `json:"quote"`
+	QuoteReal string            `json:"quote_real"`
+	Candles   []*CandleResponse `json:"candles"`
+}
+
+// PricesCandlesResponse gives the candles of multiple prices at one interval.
+type PricesCandlesResponse struct {
+	Interval string                  `json:"interval"`
+	Prices   []*PriceCandlesResponse `json:"prices"`
+}
+
+// CandlesGet gets the OHLC candles of some/all prices at an interval, optionally limited to the from/to time range.
+func (s *Service) CandlesGet(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	filter, err := parsePriceFilter(r)
+	fields := filter.fields()
+	fields["interval"] = r.URL.Query().Get("interval")
+	fields["from"] = r.URL.Query().Get("from")
+	fields["to"] = r.URL.Query().Get("to")
+	log.WithContext(r.Context()).WithFields(fields).Debug("GET /candles")
+	if err != nil {
+		writeError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	interval, err := s.parseInterval(r.URL.Query().Get("interval"))
+	if err != nil {
+		writeError(w, err, http.StatusBadRequest)
+		return
+	}
+	from, err := parseTime(r.URL.Query().Get("from"))
+	if err != nil {
+		writeError(w, fmt.Errorf("failed to parse from: %w", err), http.StatusBadRequest)
+		return
+	}
+	to, err := parseTime(r.URL.Query().Get("to"))
+	if err != nil {
+		writeError(w, fmt.Errorf("failed to parse to: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	response := PricesCandlesResponse{
+		Interval: interval.String(),
+		Prices:   make([]*PriceCandlesResponse, 0),
+	}
+
+	for k := range s.pe.GetPrices() {
+		if !filter.matches(k) {
+			continue
+		}
+
+		candles, err := s.pe.GetCandles(k, interval, from, to)
+		if err != nil {
+			continue
+		}
+
+		returnedBase, returnedQuote := returnedBaseQuote(k)
+		priceCandles := &PriceCandlesResponse{
+			Source:    k.Source,
+			Base:      returnedBase,
+			BaseReal:  k.Base,
+			Quote:     returnedQuote,
+			QuoteReal: k.Quote,
+			Candles:   make([]*CandleResponse, 0, len(candles)),
+		}
+		for _, candle := range candles {
+			high, low := applyFactor(candle.High, k.Factor), applyFactor(candle.Low, k.Factor)
+			if k.Factor < 0 {
+				high, low = low, high
+			}
+			priceCandles.Candles = append(priceCandles.Candles, &CandleResponse{
+				Time:     formatTime(candle.Start.UTC()),
+				Open:     applyFactor(candle.Open, k.Factor),
+				High:     high,
+				Low:      low,
+				Close:    applyFactor(candle.Close, k.Factor),
+				Updates:  candle.Updates,
+				Wandered: candle.Wandered,
+				Scenario: candle.Scenario,
+			})
+		}
+		response.Prices = append(response.Prices, priceCandles)
+	}
+	writeSuccess(w, response, http.StatusOK)
+}
+
+// parseInterval parses a candle interval given either as a duration (e.g. "5m") or as seconds. It must be one
+// of the intervals candles are built at.
+func (s *Service) parseInterval(str string) (time.Duration, error) {
+	intervals := s.pe.CandleIntervals()
+	if str == "" {
+		return 0, fmt.Errorf("missing interval, one of %v", intervals)
+	}
+
+	interval, err := time.ParseDuration(str)
+	if err != nil {
+		seconds, errSeconds := strconv.Atoi(str)
+		if errSeconds != nil {
+			return 0, fmt.Errorf("failed to parse interval: %w", err)
+		}
+		interval = time.Duration(seconds) * time.Second
+	}
+
+	for _, i := range intervals {
+		if i == interval {
+			return interval, nil
+		}
+	}
+	return 0, fmt.Errorf("no candles at interval %s, one of %v", interval, intervals)
+}
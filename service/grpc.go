@@ -0,0 +1,314 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"code.vegaprotocol.io/priceproxy/config"
+	"code.vegaprotocol.io/priceproxy/pricing"
+	priceproxyv1 "code.vegaprotocol.io/priceproxy/proto/priceproxy/v1"
+)
+
+// grpcUpdateBuffer is how many updates may be waiting for a WatchPrices client before it is disconnected as
+// too slow.
+const grpcUpdateBuffer = 1024
+
+// grpcServer implements priceproxyv1.PriceProxyServiceServer with the price engine of the service.
+type grpcServer struct {
+	priceproxyv1.UnimplementedPriceProxyServiceServer
+
+	s *Service
+}
+
+func (s *Service) getGRPCServer() *grpc.Server {
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(grpcUnaryTracing),
+		grpc.ChainStreamInterceptor(grpcStreamTracing),
+	)
+	priceproxyv1.RegisterPriceProxyServiceServer(server, &grpcServer{s: s})
+
+	s.health = health.NewServer()
+	healthpb.RegisterHealthServer(server, s.health)
+	reflection.Register(server)
+
+	return server
+}
+
+// StartGRPC starts the gRPC server if server.grpclisten is set, and returns the server's exit error (if any).
+func (s *Service) StartGRPC() error {
+	if s.grpc == nil {
+		return nil
+	}
+
+	listener, err := net.Listen("tcp", s.config.Server.GRPCListen)
+	if err != nil {
+		return fmt.Errorf("failed to listen: %w", err)
+	}
+
+	log.WithFields(log.Fields{
+		"listen": s.config.Server.GRPCListen,
+	}).Info("Listening for gRPC")
+	s.health.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	s.health.SetServingStatus(priceproxyv1.PriceProxyService_ServiceDesc.ServiceName, healthpb.HealthCheckResponse_SERVING)
+	return s.grpc.Serve(listener)
+}
+
+// stopGRPC lets in-flight gRPC calls finish, for up to wait.
+func (s *Service) stopGRPC(wait time.Duration) {
+	if s.grpc == nil {
+		return
+	}
+
+	s.health.Shutdown()
+
+	stopped := make(chan struct{})
+	go func() {
+		s.grpc.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(wait):
+		log.Info("gRPC server shutdown timed out")
+		s.grpc.Stop()
+	}
+}
+
+func (g *grpcServer) ListPrices(ctx context.Context, req *priceproxyv1.ListPricesRequest) (*priceproxyv1.ListPricesResponse, error) {
+	filter := grpcPriceFilter(req.GetFilter())
+	log.WithContext(ctx).WithFields(filter.fields()).Debug("gRPC ListPrices")
+
+	return &priceproxyv1.ListPricesResponse{
+		Prices: g.prices(filter, g.s.pe.GetPrices(), time.Now()),
+	}, nil
+}
+
+func (g *grpcServer) GetPrice(ctx context.Context, req *priceproxyv1.GetPriceRequest) (*priceproxyv1.GetPriceResponse, error) {
+	if req.GetSource() == "" || req.GetBase() == "" || req.GetQuote() == "" {
+		return nil, status.Error(codes.InvalidArgument, "source, base and quote are required")
+	}
+	filter := priceFilter{
+		source: req.GetSource(),
+		base:   req.GetBase(),
+		quote:  req.GetQuote(),
+		wander: req.Wander,
+	}
+	log.WithContext(ctx).WithFields(filter.fields()).Debug("gRPC GetPrice")
+
+	prices := g.prices(filter, g.s.pe.GetPrices(), time.Now())
+	switch len(prices) {
+	case 0:
+		return nil, status.Errorf(codes.NotFound, "price not found: %s %s/%s", req.GetSource(), req.GetBase(), req.GetQuote())
+	case 1:
+		return &priceproxyv1.GetPriceResponse{Price: prices[0]}, nil
+	default:
+		return nil, status.Errorf(codes.InvalidArgument, "%d prices match %s %s/%s, set wander", len(prices), req.GetSource(), req.GetBase(), req.GetQuote())
+	}
+}
+
+func (g *grpcServer) ListSources(ctx context.Context, req *priceproxyv1.ListSourcesRequest) (*priceproxyv1.ListSourcesResponse, error) {
+	sources, err := g.s.pe.GetSources()
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	sort.Slice(sources, func(i, j int) bool { return sources[i].Name < sources[j].Name })
+
+	paused := g.pausedSources()
+	response := &priceproxyv1.ListSourcesResponse{
+		Sources: make([]*priceproxyv1.Source, 0, len(sources)),
+	}
+	for _, sourcecfg := range sources {
+		response.Sources = append(response.Sources, sourceProto(sourcecfg, paused[sourcecfg.Name]))
+	}
+	return response, nil
+}
+
+func (g *grpcServer) GetSource(ctx context.Context, req *priceproxyv1.GetSourceRequest) (*priceproxyv1.GetSourceResponse, error) {
+	sourcecfg, err := g.s.pe.GetSource(req.GetName())
+	if err != nil {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+
+	return &priceproxyv1.GetSourceResponse{
+		Source: sourceProto(sourcecfg, g.pausedSources()[sourcecfg.Name]),
+	}, nil
+}
+
+func (g *grpcServer) WatchPrices(req *priceproxyv1.WatchPricesRequest, stream priceproxyv1.PriceProxyService_WatchPricesServer) error {
+	filter := grpcPriceFilter(req.GetFilter())
+	log.WithContext(stream.Context()).WithFields(filter.fields()).Debug("gRPC WatchPrices")
+
+	// subscribe before taking the snapshot, so that no update is missed in between
+	updates, unsubscribe := g.s.pe.Subscribe(grpcUpdateBuffer)
+	defer unsubscribe()
+
+	err := stream.Send(&priceproxyv1.WatchPricesResponse{
+		Event: &priceproxyv1.WatchPricesResponse_Snapshot{
+			Snapshot: &priceproxyv1.PriceSnapshot{Prices: g.prices(filter, g.s.pe.GetPrices(), time.Now())},
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	for {
+		select {
+		case update, ok := <-updates:
+			if !ok {
+				return status.Error(codes.ResourceExhausted, "too slow to keep up with price updates")
+			}
+			if !filter.matches(update.Price) {
+				continue
+			}
+			freshness := pricing.GetFreshness(update.Info, g.s.pe.PriceMaxAge(update.Price), time.Now())
+			if !filter.matchesFreshness(freshness) {
+				continue
+			}
+			err := stream.Send(&priceproxyv1.WatchPricesResponse{
+				Event: &priceproxyv1.WatchPricesResponse_Update{
+					Update: g.priceProto(update.Price, update.Info, freshness),
+				},
+			})
+			if err != nil {
+				return err
+			}
+
+		case <-g.s.closing:
+			return status.Error(codes.Unavailable, "shutting down")
+
+		case <-stream.Context().Done():
+			if errors.Is(stream.Context().Err(), context.DeadlineExceeded) {
+				return status.Error(codes.DeadlineExceeded, "deadline exceeded")
+			}
+			return status.Error(codes.Canceled, "cancelled")
+		}
+	}
+}
+
+func (g *grpcServer) pausedSources() map[string]bool {
+	paused := map[string]bool{}
+	for _, name := range g.s.pe.PausedSources() {
+		paused[name] = true
+	}
+	return paused
+}
+
+func grpcPriceFilter(f *priceproxyv1.PriceFilter) priceFilter {
+	if f == nil {
+		return priceFilter{}
+	}
+	return priceFilter{
+		source: f.GetSource(),
+		base:   f.GetBase(),
+		quote:  f.GetQuote(),
+		wander: f.Wander,
+		stale:  f.Stale,
+	}
+}
+
+// prices gives the prices matching the filter, as priceResponses does for the REST API.
+func (g *grpcServer) prices(filter priceFilter, prices map[config.PriceConfig]pricing.PriceInfo, now time.Time) []*priceproxyv1.Price {
+	result := make([]*priceproxyv1.Price, 0)
+	for k, v := range prices {
+		freshness := pricing.GetFreshness(v, g.s.pe.PriceMaxAge(k), now)
+		if filter.matches(k) && filter.matchesFreshness(freshness) {
+			result = append(result, g.priceProto(k, v, freshness))
+		}
+	}
+	return result
+}
+
+var grpcPriceStatus = map[pricing.PriceStatus]priceproxyv1.PriceStatus{
+	pricing.PriceStatusNeverFetched: priceproxyv1.PriceStatus_PRICE_STATUS_NEVER_FETCHED,
+	pricing.PriceStatusFresh:        priceproxyv1.PriceStatus_PRICE_STATUS_FRESH,
+	pricing.PriceStatusStale:        priceproxyv1.PriceStatus_PRICE_STATUS_STALE,
+}
+
+func (g *grpcServer) priceProto(k config.PriceConfig, v pricing.PriceInfo, freshness pricing.Freshness) *priceproxyv1.Price {
+	returnedBase, returnedQuote := returnedBaseQuote(k)
+
+	price := &priceproxyv1.Price{
+		Source:            k.Source,
+		Base:              returnedBase,
+		BaseReal:          k.Base,
+		Quote:             returnedQuote,
+		QuoteReal:         k.Quote,
+		Price:             applyFactor(v.Price, k.Factor).String(),
+		LastUpdatedReal:   timestampProto(v.LastUpdatedReal),
+		LastUpdatedWander: timestampProto(v.LastUpdatedWander),
+		Status:            grpcPriceStatus[freshness.Status],
+		Stale:             freshness.Stale,
+		Age:               durationpb.New(freshness.Age),
+		Restored:          v.Restored,
+		Wander:            k.Wander,
+		Scenario:          v.Scenario,
+	}
+
+	if rejections := g.s.pe.GetRejections(k); len(rejections.Counts) > 0 {
+		price.Rejected = make(map[string]uint64, len(rejections.Counts))
+		for reason, count := range rejections.Counts {
+			price.Rejected[string(reason)] = count
+		}
+	}
+
+	for _, c := range v.Contributions {
+		price.Contributions = append(price.Contributions, &priceproxyv1.Contribution{
+			Source:          c.Source,
+			Base:            c.Base,
+			Quote:           c.Quote,
+			Price:           applyFactor(c.Price, k.Factor).String(),
+			Weight:          c.Weight,
+			LastUpdatedReal: timestampProto(c.LastUpdatedReal),
+			Used:            c.Used,
+		})
+	}
+
+	for _, step := range v.Conversion {
+		price.Conversion = append(price.Conversion, &priceproxyv1.ConversionStep{
+			Source:          step.Source,
+			Base:            step.Base,
+			Quote:           step.Quote,
+			Price:           step.Price.String(),
+			Inverted:        step.Inverted,
+			LastUpdatedReal: timestampProto(step.LastUpdatedReal),
+		})
+	}
+
+	return price
+}
+
+func sourceProto(sourcecfg config.SourceConfig, paused bool) *priceproxyv1.Source {
+	return &priceproxyv1.Source{
+		Name:              sourcecfg.Name,
+		Type:              sourcecfg.SourceType(),
+		Url:               sourcecfg.URL.String(),
+		SleepReal:         durationpb.New(time.Duration(sourcecfg.SleepReal) * time.Second),
+		SleepWander:       durationpb.New(time.Duration(sourcecfg.SleepWander) * time.Second),
+		MaxAge:            durationpb.New(time.Duration(sourcecfg.MaxAge) * time.Second),
+		Batch:             sourcecfg.Batch,
+		ConversionSources: sourcecfg.ConversionSources,
+		Paused:            paused,
+	}
+}
+
+// timestampProto leaves out the zero and unix epoch times used for prices which were never fetched or wandered.
+func timestampProto(t time.Time) *timestamppb.Timestamp {
+	if t.Unix() <= 0 {
+		return nil
+	}
+	return timestamppb.New(t)
+}
@@ -0,0 +1,136 @@
+package service
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+
+	"code.vegaprotocol.io/priceproxy/config"
+	"code.vegaprotocol.io/priceproxy/pricing"
+	priceproxyv1 "code.vegaprotocol.io/priceproxy/proto/priceproxy/v1"
+)
+
+// newTestGRPCClient serves the gRPC API of a test service over an in-memory connection.
+func newTestGRPCClient(t *testing.T, s *Service) priceproxyv1.PriceProxyServiceClient {
+	t.Helper()
+
+	listener := bufconn.Listen(1 << 20)
+	s.grpc = s.getGRPCServer()
+	go func() {
+		_ = s.grpc.Serve(listener)
+	}()
+
+	conn, err := grpc.DialContext(context.Background(), "bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+
+	return priceproxyv1.NewPriceProxyServiceClient(conn)
+}
+
+func TestGRPC(t *testing.T) {
+	btc := config.PriceConfig{Source: "a", Base: "BTC", Quote: "USD", Factor: 1}
+	btcWander := config.PriceConfig{Source: "a", Base: "BTC", Quote: "USD", Factor: 2, Wander: true}
+	eth := config.PriceConfig{Source: "a", Base: "ETH", Quote: "USD", Factor: 1}
+	s := newTestService(t, config.PriceList{btc, btcWander, eth})
+	client := newTestGRPCClient(t, s)
+	ctx := context.Background()
+
+	// an hour old, older than three times the sleepReal of the source
+	s.pe.UpdatePrice(eth, pricing.PriceInfo{Price: decimal.NewFromInt(50), LastUpdatedReal: time.Now().Add(-time.Hour)})
+
+	list, err := client.ListPrices(ctx, &priceproxyv1.ListPricesRequest{})
+	require.NoError(t, err)
+	assert.Len(t, list.Prices, 3)
+
+	list, err = client.ListPrices(ctx, &priceproxyv1.ListPricesRequest{Filter: &priceproxyv1.PriceFilter{Base: "BTC"}})
+	require.NoError(t, err)
+	assert.Len(t, list.Prices, 2)
+
+	stale := true
+	list, err = client.ListPrices(ctx, &priceproxyv1.ListPricesRequest{Filter: &priceproxyv1.PriceFilter{Stale: &stale}})
+	require.NoError(t, err)
+	require.Len(t, list.Prices, 1)
+	assert.Equal(t, "ETH", list.Prices[0].Base)
+	assert.Equal(t, priceproxyv1.PriceStatus_PRICE_STATUS_STALE, list.Prices[0].Status)
+
+	stale = false
+	list, err = client.ListPrices(ctx, &priceproxyv1.ListPricesRequest{Filter: &priceproxyv1.PriceFilter{Stale: &stale}})
+	require.NoError(t, err)
+	assert.Len(t, list.Prices, 2)
+
+	// BTC/USD is published both with and without wander
+	_, err = client.GetPrice(ctx, &priceproxyv1.GetPriceRequest{Source: "a", Base: "BTC", Quote: "USD"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	wander := true
+	price, err := client.GetPrice(ctx, &priceproxyv1.GetPriceRequest{Source: "a", Base: "BTC", Quote: "USD", Wander: &wander})
+	require.NoError(t, err)
+	assert.Equal(t, "200", price.Price.Price)
+	assert.True(t, price.Price.Wander)
+
+	_, err = client.GetPrice(ctx, &priceproxyv1.GetPriceRequest{Source: "a", Base: "DOGE", Quote: "USD"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+
+	_, err = client.GetPrice(ctx, &priceproxyv1.GetPriceRequest{Source: "a", Base: "BTC"})
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+
+	source, err := client.GetSource(ctx, &priceproxyv1.GetSourceRequest{Name: "a"})
+	require.NoError(t, err)
+	assert.Equal(t, config.SourceTypeHTTP, source.Source.Type)
+
+	_, err = client.GetSource(ctx, &priceproxyv1.GetSourceRequest{Name: "unknown"})
+	assert.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestGRPCWatchPrices(t *testing.T) {
+	btc := config.PriceConfig{Source: "a", Base: "BTC", Quote: "USD", Factor: 1}
+	eth := config.PriceConfig{Source: "a", Base: "ETH", Quote: "USD", Factor: 1}
+	s := newTestService(t, config.PriceList{btc, eth})
+	client := newTestGRPCClient(t, s)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	stream, err := client.WatchPrices(ctx, &priceproxyv1.WatchPricesRequest{Filter: &priceproxyv1.PriceFilter{Base: "BTC"}})
+	require.NoError(t, err)
+
+	event, err := stream.Recv()
+	require.NoError(t, err)
+	snapshot := event.GetSnapshot()
+	require.NotNil(t, snapshot)
+	require.Len(t, snapshot.Prices, 1)
+	assert.Equal(t, "100", snapshot.Prices[0].Price)
+
+	// updates of prices left out by the filter are not sent
+	s.pe.UpdatePrice(eth, pricing.PriceInfo{Price: decimal.NewFromInt(50), LastUpdatedReal: time.Now()})
+	s.pe.UpdatePrice(btc, pricing.PriceInfo{Price: decimal.NewFromInt(101), LastUpdatedReal: time.Now()})
+
+	event, err = stream.Recv()
+	require.NoError(t, err)
+	update := event.GetUpdate()
+	require.NotNil(t, update)
+	assert.Equal(t, "BTC", update.Base)
+	assert.Equal(t, "101", update.Price)
+}
+
+func TestTimestampProto(t *testing.T) {
+	assert.Nil(t, timestampProto(time.Time{}))
+	assert.Nil(t, timestampProto(time.Unix(0, 0)))
+
+	now := time.Now()
+	assert.True(t, now.Equal(timestampProto(now).AsTime()))
+}
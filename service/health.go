@@ -0,0 +1,191 @@
+package service
+
+import (
+	"fmt"
+	"net/http"
+	"sort"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+
+	"code.vegaprotocol.io/priceproxy/config"
+	"code.vegaprotocol.io/priceproxy/pricing"
+)
+
+// Statuses of the health responses.
+const (
+	HealthStatusOK          = "ok"
+	HealthStatusUnavailable = "unavailable"
+)
+
+// Statuses of the sources in health responses.
+const (
+	SourceStatusOK      = "ok"
+	SourceStatusFailing = "failing"
+	SourceStatusPending = "pending"
+	SourceStatusPaused  = "paused"
+)
+
+// HealthResponse gives the readiness of the price proxy, with the detail of each source and price.
+// Reasons explains why the price proxy is not ready.
+type HealthResponse struct {
+	Status       string                  `json:"status"`
+	Reasons      []string                `json:"reasons,omitempty"`
+	Total        int                     `json:"total"`
+	NeverFetched int                     `json:"neverFetched"`
+	Stale        int                     `json:"stale"`
+	Sources      []*SourceHealthResponse `json:"sources"`
+	Prices       []*PriceHealthResponse  `json:"prices"`
+}
+
+// SourceHealthResponse gives the detail on the recent fetches of one source.
+type SourceHealthResponse struct {
+	Name        string `json:"name"`
+	Status      string `json:"status"`
+	LastSuccess string `json:"lastSuccess,omitempty"`
+	LastFailure string `json:"lastFailure,omitempty"`
+	LastError   string `json:"lastError,omitempty"`
+	Failures    int    `json:"failures"`
+}
+
+// PriceHealthResponse gives the freshness of one price. Fetched is false until the price is fetched for real,
+// which includes prices restored from a snapshot.
+type PriceHealthResponse struct {
+	Source  string  `json:"source"`
+	Base    string  `json:"base"`
+	Quote   string  `json:"quote"`
+	Wander  bool    `json:"wander"`
+	Status  string  `json:"status"`
+	Stale   bool    `json:"stale"`
+	Age     float64 `json:"age"`
+	Fetched bool    `json:"fetched"`
+}
+
+func maxStale(healthcfg *config.HealthConfig) float64 {
+	if healthcfg == nil {
+		return config.DefaultMaxStale
+	}
+	return healthcfg.MaxStale
+}
+
+// HealthzGet says the price proxy is alive. It does not depend on the upstream sources.
+func (s *Service) HealthzGet(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	status := struct {
+		Status string `json:"status"`
+	}{
+		Status: HealthStatusOK,
+	}
+	writeSuccess(w, status, http.StatusOK)
+}
+
+// ReadyzGet says whether the price proxy is ready to serve prices, with 503 Service Unavailable when it is not.
+func (s *Service) ReadyzGet(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	health := s.readiness(time.Now())
+
+	status := http.StatusOK
+	if health.Status != HealthStatusOK {
+		status = http.StatusServiceUnavailable
+	}
+	writeSuccess(w, health, status)
+}
+
+// readiness checks whether the price proxy is ready. It is not ready until every price has been fetched for real
+// once, and then whenever more than maxStale of the prices are stale.
+func (s *Service) readiness(now time.Time) *HealthResponse {
+	response := &HealthResponse{
+		Status:  HealthStatusOK,
+		Sources: s.sourcesHealth(),
+		Prices:  make([]*PriceHealthResponse, 0),
+	}
+
+	for k, v := range s.pe.GetPrices() {
+		freshness := pricing.GetFreshness(v, s.pe.PriceMaxAge(k), now)
+		returnedBase, returnedQuote := returnedBaseQuote(k)
+		price := &PriceHealthResponse{
+			Source:  k.Source,
+			Base:    returnedBase,
+			Quote:   returnedQuote,
+			Wander:  k.Wander,
+			Status:  string(freshness.Status),
+			Stale:   freshness.Stale,
+			Age:     freshness.Age.Seconds(),
+			Fetched: freshness.Status != pricing.PriceStatusNeverFetched && !v.Restored,
+		}
+		response.Prices = append(response.Prices, price)
+
+		response.Total++
+		if !price.Fetched {
+			response.NeverFetched++
+		}
+		if price.Stale {
+			response.Stale++
+		}
+	}
+	sort.Slice(response.Prices, func(i, j int) bool {
+		a, b := response.Prices[i], response.Prices[j]
+		if a.Source != b.Source {
+			return a.Source < b.Source
+		}
+		if a.Base != b.Base {
+			return a.Base < b.Base
+		}
+		return a.Quote < b.Quote
+	})
+
+	if !s.ready.Load() {
+		if response.NeverFetched > 0 {
+			response.Reasons = append(response.Reasons, fmt.Sprintf("%d of %d prices not fetched yet", response.NeverFetched, response.Total))
+		} else {
+			s.ready.Store(true)
+		}
+	}
+	if response.Total > 0 && float64(response.Stale)/float64(response.Total) > s.maxStale {
+		response.Reasons = append(response.Reasons, fmt.Sprintf("%d of %d prices stale, more than %g%%", response.Stale, response.Total, s.maxStale*100))
+	}
+	if len(response.Reasons) > 0 {
+		response.Status = HealthStatusUnavailable
+	}
+
+	return response
+}
+
+func (s *Service) sourcesHealth() []*SourceHealthResponse {
+	sources, err := s.pe.GetSources()
+	if err != nil {
+		return nil
+	}
+	sort.Slice(sources, func(i, j int) bool { return sources[i].Name < sources[j].Name })
+
+	paused := map[string]bool{}
+	for _, name := range s.pe.PausedSources() {
+		paused[name] = true
+	}
+	health := s.pe.GetSourceHealth()
+
+	result := make([]*SourceHealthResponse, 0, len(sources))
+	for _, sourcecfg := range sources {
+		h, fetched := health[sourcecfg.Name]
+		source := &SourceHealthResponse{
+			Name:      sourcecfg.Name,
+			Status:    SourceStatusOK,
+			LastError: h.LastError,
+			Failures:  h.Failures,
+		}
+		if !h.LastSuccess.IsZero() {
+			source.LastSuccess = h.LastSuccess.String()
+		}
+		if !h.LastFailure.IsZero() {
+			source.LastFailure = h.LastFailure.String()
+		}
+		switch {
+		case paused[sourcecfg.Name]:
+			source.Status = SourceStatusPaused
+		case !fetched:
+			source.Status = SourceStatusPending
+		case h.Failures > 0:
+			source.Status = SourceStatusFailing
+		}
+		result = append(result, source)
+	}
+	return result
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"code.vegaprotocol.io/priceproxy/config"
+	"code.vegaprotocol.io/priceproxy/pricing"
+)
+
+// assertAlive checks that GET /healthz and GET /status say the price proxy is alive.
+func assertAlive(t *testing.T, s *Service) {
+	t.Helper()
+
+	w := serve(s, http.MethodGet, "/healthz", "", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"status": "ok"}`, w.Body.String())
+
+	w = serve(s, http.MethodGet, "/status", "", "")
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"Status": true}`, w.Body.String())
+}
+
+func getReadiness(t *testing.T, s *Service) (int, HealthResponse) {
+	t.Helper()
+
+	w := serve(s, http.MethodGet, "/readyz", "", "")
+	var health HealthResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &health))
+	return w.Code, health
+}
+
+func TestHealthChecks(t *testing.T) {
+	btc := config.PriceConfig{Source: "a", Base: "BTC", Quote: "USD", Factor: 1}
+	eth := config.PriceConfig{Source: "a", Base: "ETH", Quote: "USD", Factor: 1}
+	s := newTestService(t, config.PriceList{btc, eth})
+
+	assertAlive(t, s)
+	code, health := getReadiness(t, s)
+	assert.Equal(t, http.StatusOK, code)
+	assert.Equal(t, HealthStatusOK, health.Status)
+	assert.Equal(t, 2, health.Total)
+	require.Len(t, health.Sources, 1)
+	assert.Equal(t, SourceStatusOK, health.Sources[0].Status)
+
+	// more than half of the prices stale: alive, but not ready
+	old := time.Now().Add(-time.Hour)
+	s.pe.UpdatePrice(btc, pricing.PriceInfo{Price: decimal.NewFromInt(100), LastUpdatedReal: old})
+	s.pe.UpdatePrice(eth, pricing.PriceInfo{Price: decimal.NewFromInt(100), LastUpdatedReal: old})
+
+	assertAlive(t, s)
+	code, health = getReadiness(t, s)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, HealthStatusUnavailable, health.Status)
+	assert.Equal(t, 2, health.Stale)
+	assert.Equal(t, []string{"2 of 2 prices stale, more than 50%"}, health.Reasons)
+}
+
+func TestHealthChecksNeverFetched(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer upstream.Close()
+
+	cfg := config.Config{
+		Server:  &config.ServerConfig{Listen: ":0"},
+		Sources: []*config.SourceConfig{testSource(t, "a", upstream.URL)},
+		Prices:  config.PriceList{{Source: "a", Base: "BTC", Quote: "USD", Factor: 1}},
+	}
+	s, err := NewService(context.Background(), cfg)
+	require.NoError(t, err)
+	defer s.Stop()
+
+	require.Eventually(t, func() bool {
+		return s.pe.GetSourceHealth()["a"].Failures > 0
+	}, 5*time.Second, 10*time.Millisecond)
+
+	assertAlive(t, s)
+	code, health := getReadiness(t, s)
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.Equal(t, 1, health.NeverFetched)
+	assert.Contains(t, health.Reasons, "1 of 1 prices not fetched yet")
+	require.Len(t, health.Sources, 1)
+	assert.Equal(t, SourceStatusFailing, health.Sources[0].Status)
+}
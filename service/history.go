@@ -0,0 +1,115 @@
+package service
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/shopspring/decimal"
+	log "github.com/sirupsen/logrus"
+)
+
+// PricePointResponse gives the detail on one point in the history of a price.
+type PricePointResponse struct {
+	Price           decimal.Decimal `json:"price"`
+	Time            string          `json:"time"`
+	LastUpdatedReal string          `json:"lastUpdatedReal"`
+	Wandered        bool            `json:"wandered"`
+	Scenario        bool            `json:"scenario"`
+}
+
+// PriceHistoryResponse gives the history of one price.
+type PriceHistoryResponse struct {
+	Source    string                `json:"source"`
+	Base      string                `json:"base"`
+	BaseReal  string                `json:"base_real"`
+	Quote     string                `json:"quote"`
+	QuoteReal string                `json:"quote_real"`
+	Points    []*PricePointResponse `json:"points"`
+}
+
+// PricesHistoryResponse gives the history of multiple prices.
+type PricesHistoryResponse struct {
+	Prices []*PriceHistoryResponse `json:"prices"`
+}
+
+// PricesHistoryGet gets the recorded history of some/all prices, optionally limited to the from/to time range.
+func (s *Service) PricesHistoryGet(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	filter, err := parsePriceFilter(r)
+	fields := filter.fields()
+	fields["from"] = r.URL.Query().Get("from")
+	fields["to"] = r.URL.Query().Get("to")
+	log.WithContext(r.Context()).WithFields(fields).Debug("GET /prices/history")
+	if err != nil {
+		writeError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	from, err := parseTime(r.URL.Query().Get("from"))
+	if err != nil {
+		writeError(w, fmt.Errorf("failed to parse from: %w", err), http.StatusBadRequest)
+		return
+	}
+	to, err := parseTime(r.URL.Query().Get("to"))
+	if err != nil {
+		writeError(w, fmt.Errorf("failed to parse to: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	response := PricesHistoryResponse{
+		Prices: make([]*PriceHistoryResponse, 0),
+	}
+
+	for k := range s.pe.GetPrices() {
+		if !filter.matches(k) {
+			continue
+		}
+
+		points, err := s.pe.GetPriceHistory(k, from, to)
+		if err != nil {
+			continue
+		}
+
+		returnedBase, returnedQuote := returnedBaseQuote(k)
+		history := &PriceHistoryResponse{
+			Source:    k.Source,
+			Base:      returnedBase,
+			BaseReal:  k.Base,
+			Quote:     returnedQuote,
+			QuoteReal: k.Quote,
+			Points:    make([]*PricePointResponse, 0, len(points)),
+		}
+		for _, point := range points {
+			history.Points = append(history.Points, &PricePointResponse{
+				Price:           applyFactor(point.Price, k.Factor),
+				Time:            formatTime(point.Time),
+				LastUpdatedReal: formatTime(point.LastUpdatedReal),
+				Wandered:        point.Wandered,
+				Scenario:        point.Scenario,
+			})
+		}
+		response.Prices = append(response.Prices, history)
+	}
+	writeSuccess(w, response, http.StatusOK)
+}
+
+// parseTime parses a query parameter given either as RFC3339 or as unix seconds. An empty string gives a zero time.
+func parseTime(s string) (time.Time, error) {
+	if s == "" {
+		return time.Time{}, nil
+	}
+
+	if seconds, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return time.Unix(seconds, 0), nil
+	}
+
+	return time.Parse(time.RFC3339, s)
+}
+
+// formatTime formats a time for the history and candles responses, as RFC3339 with nanoseconds, which parseTime
+// reads back so that a response can be paged from its last point.
+func formatTime(t time.Time) string {
+	return t.Format(time.RFC3339Nano)
+}
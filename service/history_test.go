@@ -0,0 +1,22 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatTime(t *testing.T) {
+	now := time.Date(2022, 11, 9, 13, 35, 12, 123456789, time.UTC)
+	assert.Equal(t, "2022-11-09T13:35:12.123456789Z", formatTime(now))
+
+	parsed, err := parseTime(formatTime(now))
+	require.NoError(t, err)
+	assert.True(t, now.Equal(parsed))
+
+	parsed, err = parseTime(formatTime(now.In(time.FixedZone("CET", 3600))))
+	require.NoError(t, err)
+	assert.True(t, now.Equal(parsed))
+}
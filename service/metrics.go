@@ -0,0 +1,84 @@
+package service
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// httpMetrics counts and times the requests to the REST handlers, by handler name.
+type httpMetrics struct {
+	requests *prometheus.CounterVec
+	duration *prometheus.HistogramVec
+}
+
+func newHTTPMetrics() *httpMetrics {
+	return &httpMetrics{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: "priceproxy",
+			Name:      "http_requests_total",
+			Help:      "HTTP requests, by handler, method and status code.",
+		}, []string{"handler", "method", "code"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: "priceproxy",
+			Name:      "http_request_duration_seconds",
+			Help:      "Time taken to answer HTTP requests, by handler and method.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"handler", "method"}),
+	}
+}
+
+// getMetricsHandler serves the metrics of the service, its price engine, and the Go runtime and process.
+func (s *Service) getMetricsHandler() http.Handler {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		s.httpMetrics.requests,
+		s.httpMetrics.duration,
+		s.pe,
+	)
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+// MetricsGet serves the metrics in the Prometheus format.
+func (s *Service) MetricsGet(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	s.metricsHandler.ServeHTTP(w, r)
+}
+
+// statusRecorder keeps the status code written by a handler.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Flush lets streaming handlers flush through the recorder.
+func (r *statusRecorder) Flush() {
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// instrument records the requests to a handler in the HTTP metrics, and traces them (see traced). Streams are
+// recorded when they end. The WebSocket handler, which takes over the connection, is only traced.
+func (s *Service) instrument(name string, handle httprouter.Handle) httprouter.Handle {
+	return s.traced(name, func(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+		start := time.Now()
+		recorder := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		handle(recorder, r, ps)
+
+		s.httpMetrics.requests.WithLabelValues(name, r.Method, strconv.Itoa(recorder.status)).Inc()
+		s.httpMetrics.duration.WithLabelValues(name, r.Method).Observe(time.Since(start).Seconds())
+		setSpanStatus(r.Context(), recorder.status)
+	})
+}
@@ -0,0 +1,160 @@
+package service
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	log "github.com/sirupsen/logrus"
+
+	"code.vegaprotocol.io/priceproxy/config"
+	"code.vegaprotocol.io/priceproxy/pricing"
+)
+
+// ScenarioPriceResponse gives a pair moved by a scenario.
+type ScenarioPriceResponse struct {
+	Source string `json:"source"`
+	Base   string `json:"base"`
+	Quote  string `json:"quote"`
+}
+
+// ScenarioResponse gives a configured scenario, and when it is running, when it started and when it ends.
+// Overridden lists the prices it currently overrides.
+type ScenarioResponse struct {
+	Name       string                   `json:"name"`
+	Active     bool                     `json:"active"`
+	Started    string                   `json:"started,omitempty"`
+	Ends       string                   `json:"ends,omitempty"`
+	Prices     []*ScenarioPriceResponse `json:"prices"`
+	Overridden []*PriceResponse         `json:"overridden,omitempty"`
+}
+
+// ScenariosResponse lists the scenarios.
+type ScenariosResponse struct {
+	Scenarios []*ScenarioResponse `json:"scenarios"`
+}
+
+// ScenariosGet lists the configured scenarios, and which of them are running.
+func (s *Service) ScenariosGet(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	log.WithContext(r.Context()).Debug("GET /scenarios")
+
+	s.configMu.Lock()
+	scenarios := s.config.Scenarios
+	s.configMu.Unlock()
+
+	statuses := s.pe.GetScenarios()
+	running := make(map[string]pricing.ScenarioStatus, len(statuses))
+	for _, status := range statuses {
+		running[status.Name] = status
+	}
+
+	response := ScenariosResponse{Scenarios: make([]*ScenarioResponse, 0, len(scenarios))}
+	for _, scenariocfg := range scenarios {
+		scenario := s.scenarioResponse(scenariocfg, running[scenariocfg.Name])
+		delete(running, scenariocfg.Name)
+		response.Scenarios = append(response.Scenarios, scenario)
+	}
+	// scenarios removed from the config by a reload while running
+	for _, status := range statuses {
+		if _, found := running[status.Name]; found {
+			response.Scenarios = append(response.Scenarios, s.scenarioResponse(config.ScenarioConfig{Name: status.Name}, status))
+		}
+	}
+	writeSuccess(w, response, http.StatusOK)
+}
+
+func (s *Service) scenarioResponse(scenariocfg config.ScenarioConfig, status pricing.ScenarioStatus) *ScenarioResponse {
+	response := &ScenarioResponse{
+		Name:   scenariocfg.Name,
+		Active: status.Name != "",
+		Prices: make([]*ScenarioPriceResponse, 0, len(scenariocfg.Prices)),
+	}
+	for _, scenarioPrice := range scenariocfg.Prices {
+		response.Prices = append(response.Prices, &ScenarioPriceResponse{
+			Source: scenarioPrice.Source,
+			Base:   scenarioPrice.Base,
+			Quote:  scenarioPrice.Quote,
+		})
+	}
+	if !response.Active {
+		return response
+	}
+
+	response.Started = formatTime(status.Started)
+	response.Ends = formatTime(status.Ends)
+	response.Overridden = make([]*PriceResponse, 0, len(status.Prices))
+	now := time.Now()
+	for _, pricecfg := range status.Prices {
+		pi, err := s.pe.GetPrice(pricecfg)
+		if err != nil {
+			continue
+		}
+		freshness := pricing.GetFreshness(pi, s.pe.PriceMaxAge(pricecfg), now)
+		response.Overridden = append(response.Overridden, s.priceResponse(pricecfg, pi, freshness))
+	}
+	return response
+}
+
+// AdminScenarioStart starts a configured scenario.
+func (s *Service) AdminScenarioStart(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	name := ps.ByName("name")
+
+	scenariocfg, found := s.findScenario(name)
+	if !found {
+		writeError(w, fmt.Errorf("scenario not found: %s", name), http.StatusNotFound)
+		return
+	}
+	if err := s.pe.StartScenario(scenariocfg); err != nil {
+		writeError(w, err, http.StatusConflict)
+		return
+	}
+
+	log.WithContext(r.Context()).WithFields(log.Fields{
+		"name": name,
+	}).Info("Started scenario through the admin API")
+	s.writeScenario(w, scenariocfg)
+}
+
+// AdminScenarioStop stops a running scenario, and returns its prices to their real price.
+func (s *Service) AdminScenarioStop(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	name := ps.ByName("name")
+
+	if err := s.pe.StopScenario(name); err != nil {
+		writeError(w, err, http.StatusNotFound)
+		return
+	}
+
+	log.WithContext(r.Context()).WithFields(log.Fields{
+		"name": name,
+	}).Info("Stopped scenario through the admin API")
+	scenariocfg, found := s.findScenario(name)
+	if !found {
+		scenariocfg = config.ScenarioConfig{Name: name}
+	}
+	s.writeScenario(w, scenariocfg)
+}
+
+// findScenario returns the configured scenario with the given name.
+func (s *Service) findScenario(name string) (config.ScenarioConfig, bool) {
+	s.configMu.Lock()
+	defer s.configMu.Unlock()
+
+	for _, scenariocfg := range s.config.Scenarios {
+		if scenariocfg.Name == name {
+			return scenariocfg, true
+		}
+	}
+	return config.ScenarioConfig{}, false
+}
+
+// writeScenario writes the current state of a scenario.
+func (s *Service) writeScenario(w http.ResponseWriter, scenariocfg config.ScenarioConfig) {
+	status := pricing.ScenarioStatus{}
+	for _, running := range s.pe.GetScenarios() {
+		if running.Name == scenariocfg.Name {
+			status = running
+		}
+	}
+	writeSuccess(w, s.scenarioResponse(scenariocfg, status), http.StatusOK)
+}
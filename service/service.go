@@ -4,16 +4,23 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net"
 	"net/http"
+	"reflect"
 	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"code.vegaprotocol.io/priceproxy/config"
 	"code.vegaprotocol.io/priceproxy/pricing"
 
 	"github.com/julienschmidt/httprouter"
+	"github.com/shopspring/decimal"
 	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
 )
 
 // ErrorResponse is used when something went wrong.
@@ -25,21 +32,78 @@ type ErrorResponse struct {
 type Service struct {
 	*httprouter.Router
 
-	config config.Config
-	server *http.Server
-	pe     pricing.Engine
+	config     config.Config
+	configMu   sync.Mutex
+	configFile string
+	adminToken string
+	server     *http.Server
+	grpc       *grpc.Server
+	health     *health.Server
+	pe         pricing.Engine
+
+	httpMetrics    *httpMetrics
+	metricsHandler http.Handler
+
+	// ready is set once every price has been fetched, maxStale is the fraction of prices which may then be stale
+	ready    atomic.Bool
+	maxStale float64
+
+	// closing is closed by Stop, to end the streams which outlive server shutdown
+	closing chan struct{}
+	started time.Time
+}
+
+// ServiceOption configures optional features of the service.
+type ServiceOption func(s *Service)
+
+// WithConfigFile sets the file the config was read from, which admin API changes are written back to when
+// enabled (see config.AdminConfig).
+func WithConfigFile(path string) ServiceOption {
+	return func(s *Service) {
+		s.configFile = path
+	}
 }
 
 // PriceResponse gives the detail on one price.
 type PriceResponse struct {
-	Source            string  `json:"source"`
-	Base              string  `json:"base"`
-	BaseReal          string  `json:"base_real"`
-	Quote             string  `json:"quote"`
-	QuoteReal         string  `json:"quote_real"`
-	Price             float64 `json:"price"`
-	LastUpdatedReal   string  `json:"lastUpdatedReal"`
-	LastUpdatedWander string  `json:"lastUpdatedWander"`
+	Source            string          `json:"source"`
+	Base              string          `json:"base"`
+	BaseReal          string          `json:"base_real"`
+	Quote             string          `json:"quote"`
+	QuoteReal         string          `json:"quote_real"`
+	Price             decimal.Decimal `json:"price"`
+	LastUpdatedReal   string          `json:"lastUpdatedReal"`
+	LastUpdatedWander string          `json:"lastUpdatedWander"`
+	Status            string          `json:"status"`
+	Stale             bool            `json:"stale"`
+	Age               float64         `json:"age"`
+	Restored          bool            `json:"restored"`
+	Scenario          string          `json:"scenario,omitempty"`
+
+	Rejected      map[string]uint64         `json:"rejected,omitempty"`
+	Contributions []*ContributionResponse   `json:"contributions,omitempty"`
+	Conversion    []*ConversionStepResponse `json:"conversion,omitempty"`
+}
+
+// ConversionStepResponse gives the detail on one rate used to convert a price which was not quoted directly.
+type ConversionStepResponse struct {
+	Source          string          `json:"source"`
+	Base            string          `json:"base"`
+	Quote           string          `json:"quote"`
+	Price           decimal.Decimal `json:"price"`
+	Inverted        bool            `json:"inverted"`
+	LastUpdatedReal string          `json:"lastUpdatedReal"`
+}
+
+// ContributionResponse gives the detail on what one source contributed to an aggregated price.
+type ContributionResponse struct {
+	Source          string          `json:"source"`
+	Base            string          `json:"base"`
+	Quote           string          `json:"quote"`
+	Price           decimal.Decimal `json:"price"`
+	Weight          float64         `json:"weight"`
+	LastUpdatedReal string          `json:"lastUpdatedReal"`
+	Used            bool            `json:"used"`
 }
 
 // PricesResponse gives details on multiple prices.
@@ -48,29 +112,55 @@ type PricesResponse struct {
 }
 
 // NewService creates a new service instance (with optional mocks for test purposes).
-func NewService(config config.Config) (*Service, error) {
+// The price fetchers run until ctx is cancelled or Stop is called.
+func NewService(ctx context.Context, config config.Config, opts ...ServiceOption) (*Service, error) {
 	s := &Service{
-		Router: httprouter.New(),
-		config: config,
-		server: nil,
-		pe:     nil,
+		Router:  httprouter.New(),
+		config:  config,
+		server:  nil,
+		pe:      nil,
+		closing: make(chan struct{}),
+		started: time.Now(),
+
+		httpMetrics: newHTTPMetrics(),
+		maxStale:    maxStale(config.Health),
 	}
 
-	if err := s.initPricingEngine(); err != nil {
+	for _, opt := range opts {
+		opt(s)
+	}
+
+	if err := s.initPricingEngine(ctx); err != nil {
 		return nil, fmt.Errorf("failed to initialise price engine: %s", err.Error())
 	}
 
+	s.metricsHandler = s.getMetricsHandler()
 	s.addRoutes()
 	s.server = s.getServer()
+	if s.config.Server.GRPCListen != "" {
+		s.grpc = s.getGRPCServer()
+	}
 
 	return s, nil
 }
 
 func (s *Service) addRoutes() {
-	s.GET("/prices", s.PricesGet)
-	s.GET("/sources", s.SourcesGet)
-	s.GET("/sources/:name", s.SourceGet)
-	s.GET("/status", s.StatusGet)
+	s.GET("/prices", s.instrument("PricesGet", s.PricesGet))
+	s.GET("/prices/history", s.instrument("PricesHistoryGet", s.PricesHistoryGet))
+	s.GET("/prices/stream", s.instrument("PricesStream", s.PricesStream))
+	s.GET("/candles", s.instrument("CandlesGet", s.CandlesGet))
+	s.GET("/scenarios", s.instrument("ScenariosGet", s.ScenariosGet))
+	s.GET("/sources", s.instrument("SourcesGet", s.SourcesGet))
+	s.GET("/sources/:name", s.instrument("SourceGet", s.SourceGet))
+	s.GET("/status", s.instrument("StatusGet", s.StatusGet))
+	s.GET("/healthz", s.instrument("HealthzGet", s.HealthzGet))
+	s.GET("/readyz", s.instrument("ReadyzGet", s.ReadyzGet))
+	s.GET("/ws/prices", s.traced("PricesWebSocket", s.PricesWebSocket))
+	s.GET("/metrics", s.MetricsGet)
+
+	if s.config.Admin != nil {
+		s.addAdminRoutes()
+	}
 }
 
 func (s *Service) getServer() *http.Server {
@@ -82,9 +172,24 @@ func (s *Service) getServer() *http.Server {
 		IdleTimeout:    time.Second * 60,
 		MaxHeaderBytes: 1 << 20,
 		Handler:        handler,
+		ConnContext:    connContext,
 	}
 }
 
+type connContextKey struct{}
+
+// connContext keeps the connection of a request in its context, so that streaming handlers can replace the
+// write deadline set by the server's write timeout.
+func connContext(ctx context.Context, conn net.Conn) context.Context {
+	return context.WithValue(ctx, connContextKey{}, conn)
+}
+
+// requestConn returns the connection of a request, or nil if the server does not keep it (see connContext).
+func requestConn(r *http.Request) net.Conn {
+	conn, _ := r.Context().Value(connContextKey{}).(net.Conn)
+	return conn
+}
+
 // Start starts the HTTP server, and returns the server's exit error (if any).
 func (s *Service) Start() error {
 	log.WithFields(log.Fields{
@@ -93,13 +198,15 @@ func (s *Service) Start() error {
 	return s.server.ListenAndServe()
 }
 
-// Stop stops the HTTP service.
+// Stop stops the HTTP and gRPC services, then the price fetchers.
 func (s *Service) Stop() {
 	wait := 2 * time.Second
 	log.WithFields(log.Fields{
 		"listen": s.config.Server.Listen,
 	}).Info("Shutting down")
 
+	close(s.closing)
+
 	ctx, cancel := context.WithTimeout(context.Background(), wait)
 	defer cancel()
 	err := s.server.Shutdown(ctx)
@@ -108,10 +215,62 @@ func (s *Service) Stop() {
 			"err": err.Error(),
 		}).Info("Server shutdown failed")
 	}
+	s.stopGRPC(wait)
+
+	s.pe.Stop()
+	log.Info("Price fetchers stopped")
+}
+
+// Reload applies the prices, sources and scenarios of a new config to the running service. Running scenarios
+// keep their timeline. The server, history, candles, snapshot, admin, health and tracing settings are only read
+// at startup, changes to them are logged and otherwise ignored.
+func (s *Service) Reload(cfg config.Config) error {
+	s.configMu.Lock()
+	defer s.configMu.Unlock()
+
+	return s.reload(cfg)
 }
 
-func (s *Service) initPricingEngine() error {
-	s.pe = pricing.NewEngine(s.config.Prices)
+// reload applies a new config. It must be called with configMu held.
+func (s *Service) reload(cfg config.Config) error {
+	if !reflect.DeepEqual(s.config.Server, cfg.Server) ||
+		!reflect.DeepEqual(s.config.History, cfg.History) ||
+		!reflect.DeepEqual(s.config.Candles, cfg.Candles) ||
+		!reflect.DeepEqual(s.config.Snapshot, cfg.Snapshot) ||
+		!reflect.DeepEqual(s.config.Admin, cfg.Admin) ||
+		!reflect.DeepEqual(s.config.Health, cfg.Health) ||
+		!reflect.DeepEqual(s.config.Tracing, cfg.Tracing) {
+		log.Warn("Changes to the server, history, candles, snapshot, admin, health and tracing config need a restart, ignoring them")
+	}
+
+	sources := make([]config.SourceConfig, 0, len(cfg.Sources))
+	for _, sourcecfg := range cfg.Sources {
+		sources = append(sources, *sourcecfg)
+	}
+
+	if err := s.pe.Reload(cfg.Prices, sources); err != nil {
+		return fmt.Errorf("failed to reload price engine: %w", err)
+	}
+
+	s.config.Prices = cfg.Prices
+	s.config.Sources = cfg.Sources
+	s.config.Scenarios = cfg.Scenarios
+	return nil
+}
+
+func (s *Service) initPricingEngine(ctx context.Context) error {
+	opts := []pricing.EngineOption{}
+	if s.config.History != nil {
+		opts = append(opts, pricing.WithHistory(*s.config.History))
+	}
+	if s.config.Candles != nil {
+		opts = append(opts, pricing.WithCandles(*s.config.Candles))
+	}
+	if s.config.Snapshot != nil {
+		opts = append(opts, pricing.WithSnapshot(*s.config.Snapshot))
+	}
+
+	s.pe = pricing.NewEngine(s.config.Prices, opts...)
 	for _, sourcecfg := range s.config.Sources {
 		err := s.pe.AddSource(*sourcecfg)
 		if err != nil {
@@ -123,71 +282,183 @@ func (s *Service) initPricingEngine() error {
 			}).Fatal("Failed to add source")
 		}
 		log.WithFields(log.Fields{
-			"name":      sourcecfg.Name,
-			"sleepReal": sourcecfg.SleepReal,
-			"url":       sourcecfg.URL.String(),
+			"name":        sourcecfg.Name,
+			"sleepReal":   sourcecfg.SleepReal,
+			"sleepWander": sourcecfg.SleepWander,
+			"url":         sourcecfg.URL.String(),
 		}).Info("Added source")
 	}
 
-	if err := s.pe.StartFetching(); err != nil {
+	if err := s.pe.StartFetching(ctx); err != nil {
 		return fmt.Errorf("failed to start fetching: %w", err)
 	}
 
 	return nil
 }
 
-// PricesGet gets information on all prices.
-func (s *Service) PricesGet(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
-	source := r.URL.Query().Get("source")
-	base := r.URL.Query().Get("base")
-	quote := r.URL.Query().Get("quote")
-	var wanderPtr *bool
-	wanderString := r.URL.Query().Get("wander")
-	log.WithFields(log.Fields{
-		"base":   base,
-		"quote":  quote,
-		"source": source,
-		"wander": wanderString,
-	}).Debug("GET /prices")
-	if wanderString != "" {
+// priceFilter holds the query parameters used to select prices, see PricesGet.
+type priceFilter struct {
+	source string
+	base   string
+	quote  string
+	wander *bool
+	stale  *bool
+}
+
+func parsePriceFilter(r *http.Request) (priceFilter, error) {
+	f := priceFilter{
+		source: r.URL.Query().Get("source"),
+		base:   r.URL.Query().Get("base"),
+		quote:  r.URL.Query().Get("quote"),
+	}
+
+	if wanderString := r.URL.Query().Get("wander"); wanderString != "" {
 		wander, err := strconv.ParseBool(wanderString)
 		if err != nil {
-			writeError(w, fmt.Errorf("failed to parse wander as boolean"), http.StatusInternalServerError)
-			return
+			return f, fmt.Errorf("failed to parse wander as boolean")
 		}
-		wanderPtr = &wander
+		f.wander = &wander
 	}
 
-	response := PricesResponse{
-		Prices: make([]*PriceResponse, 0),
-	}
-
-	for k, v := range s.pe.GetPrices() {
-		if (source == "" || source == k.Source) &&
-			(base == "" || strings.EqualFold(base, k.Base) || strings.EqualFold(base, k.BaseOverride)) &&
-			(quote == "" || strings.EqualFold(quote, k.Quote) || strings.EqualFold(quote, k.QuoteOverride)) &&
-			(wanderPtr == nil || *wanderPtr == k.Wander) {
-			returnedQuote := k.Quote
-			if k.QuoteOverride != "" {
-				returnedQuote = k.QuoteOverride
-			}
-			returnedBase := k.Base
-			if k.BaseOverride != "" {
-				returnedBase = k.BaseOverride
-			}
-
-			response.Prices = append(response.Prices, &PriceResponse{
-				Source:            k.Source,
-				Base:              returnedBase,
-				BaseReal:          k.Base,
-				Quote:             returnedQuote,
-				QuoteReal:         k.Quote,
-				Price:             v.Price * k.Factor,
-				LastUpdatedReal:   v.LastUpdatedReal.String(),
-				LastUpdatedWander: v.LastUpdatedWander.String(),
-			})
+	if staleString := r.URL.Query().Get("stale"); staleString != "" {
+		stale, err := strconv.ParseBool(staleString)
+		if err != nil {
+			return f, fmt.Errorf("failed to parse stale as boolean")
+		}
+		f.stale = &stale
+	}
+
+	return f, nil
+}
+
+func (f priceFilter) matches(k config.PriceConfig) bool {
+	return (f.source == "" || f.source == k.Source) &&
+		(f.base == "" || strings.EqualFold(f.base, k.Base) || strings.EqualFold(f.base, k.BaseOverride)) &&
+		(f.quote == "" || strings.EqualFold(f.quote, k.Quote) || strings.EqualFold(f.quote, k.QuoteOverride)) &&
+		(f.wander == nil || *f.wander == k.Wander)
+}
+
+func (f priceFilter) matchesFreshness(freshness pricing.Freshness) bool {
+	return f.stale == nil || *f.stale == freshness.Stale
+}
+
+func (f priceFilter) fields() log.Fields {
+	wander := ""
+	if f.wander != nil {
+		wander = strconv.FormatBool(*f.wander)
+	}
+	stale := ""
+	if f.stale != nil {
+		stale = strconv.FormatBool(*f.stale)
+	}
+	return log.Fields{
+		"base":   f.base,
+		"quote":  f.quote,
+		"source": f.source,
+		"wander": wander,
+		"stale":  stale,
+	}
+}
+
+// returnedBaseQuote gives the base and quote a price is published as, taking overrides into account.
+func returnedBaseQuote(k config.PriceConfig) (string, string) {
+	returnedQuote := k.Quote
+	if k.QuoteOverride != "" {
+		returnedQuote = k.QuoteOverride
+	}
+	returnedBase := k.Base
+	if k.BaseOverride != "" {
+		returnedBase = k.BaseOverride
+	}
+	return returnedBase, returnedQuote
+}
+
+// applyFactor multiplies a price by the factor configured for it.
+func applyFactor(price decimal.Decimal, factor float64) decimal.Decimal {
+	return price.Mul(decimal.NewFromFloat(factor))
+}
+
+// priceResponses gives the prices matching the filter.
+func (s *Service) priceResponses(filter priceFilter, prices map[config.PriceConfig]pricing.PriceInfo, now time.Time) []*PriceResponse {
+	responses := make([]*PriceResponse, 0)
+	for k, v := range prices {
+		freshness := pricing.GetFreshness(v, s.pe.PriceMaxAge(k), now)
+		if filter.matches(k) && filter.matchesFreshness(freshness) {
+			responses = append(responses, s.priceResponse(k, v, freshness))
 		}
 	}
+	return responses
+}
+
+// priceResponse gives the detail on one price, as published with its factor and overrides.
+func (s *Service) priceResponse(k config.PriceConfig, v pricing.PriceInfo, freshness pricing.Freshness) *PriceResponse {
+	returnedBase, returnedQuote := returnedBaseQuote(k)
+
+	var rejected map[string]uint64
+	if rejections := s.pe.GetRejections(k); len(rejections.Counts) > 0 {
+		rejected = make(map[string]uint64, len(rejections.Counts))
+		for reason, count := range rejections.Counts {
+			rejected[string(reason)] = count
+		}
+	}
+
+	var contributions []*ContributionResponse
+	for _, c := range v.Contributions {
+		contributions = append(contributions, &ContributionResponse{
+			Source:          c.Source,
+			Base:            c.Base,
+			Quote:           c.Quote,
+			Price:           applyFactor(c.Price, k.Factor),
+			Weight:          c.Weight,
+			LastUpdatedReal: c.LastUpdatedReal.String(),
+			Used:            c.Used,
+		})
+	}
+
+	var conversion []*ConversionStepResponse
+	for _, step := range v.Conversion {
+		conversion = append(conversion, &ConversionStepResponse{
+			Source:          step.Source,
+			Base:            step.Base,
+			Quote:           step.Quote,
+			Price:           step.Price,
+			Inverted:        step.Inverted,
+			LastUpdatedReal: step.LastUpdatedReal.String(),
+		})
+	}
+
+	return &PriceResponse{
+		Source:            k.Source,
+		Base:              returnedBase,
+		BaseReal:          k.Base,
+		Quote:             returnedQuote,
+		QuoteReal:         k.Quote,
+		Price:             applyFactor(v.Price, k.Factor),
+		LastUpdatedReal:   v.LastUpdatedReal.String(),
+		LastUpdatedWander: v.LastUpdatedWander.String(),
+		Status:            string(freshness.Status),
+		Stale:             freshness.Stale,
+		Age:               freshness.Age.Seconds(),
+		Restored:          v.Restored,
+		Scenario:          v.Scenario,
+		Rejected:          rejected,
+		Contributions:     contributions,
+		Conversion:        conversion,
+	}
+}
+
+// PricesGet gets information on all prices.
+func (s *Service) PricesGet(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	filter, err := parsePriceFilter(r)
+	log.WithContext(r.Context()).WithFields(filter.fields()).Debug("GET /prices")
+	if err != nil {
+		writeError(w, err, http.StatusInternalServerError)
+		return
+	}
+
+	response := PricesResponse{
+		Prices: s.priceResponses(filter, s.pe.GetPrices(), time.Now()),
+	}
 	writeSuccess(w, response, http.StatusOK)
 }
 
@@ -215,7 +486,8 @@ func (s *Service) SourcesGet(w http.ResponseWriter, r *http.Request, ps httprout
 	writeSuccess(w, sources, http.StatusOK)
 }
 
-// StatusGet says all is well.
+// StatusGet says all is well. Like GET /healthz, it does not depend on the upstream sources, see GET /readyz
+// for that.
 func (s *Service) StatusGet(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
 	status := struct {
 		Status bool
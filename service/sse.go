@@ -0,0 +1,195 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	log "github.com/sirupsen/logrus"
+
+	"code.vegaprotocol.io/priceproxy/pricing"
+)
+
+const (
+	// sseWriteWait is how long a client gets to accept one event.
+	sseWriteWait = 10 * time.Second
+	// sseKeepAlivePeriod is how often a comment is sent when there are no updates, so that proxies keep
+	// the connection open and dead clients are noticed.
+	sseKeepAlivePeriod = 15 * time.Second
+	// sseUpdateBuffer is how many updates may be waiting for a client before it is disconnected as too slow.
+	sseUpdateBuffer = 1024
+)
+
+// Event types sent on /prices/stream.
+const (
+	StreamSnapshot = "snapshot"
+	StreamUpdate   = "update"
+)
+
+// sseStream writes events to the response, flushing each one. The response has no length, it ends when the
+// handler returns.
+type sseStream struct {
+	w       http.ResponseWriter
+	flusher http.Flusher
+	conn    net.Conn
+}
+
+func (st *sseStream) writeEvent(id, event string, data interface{}) error {
+	body, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	return st.write(fmt.Sprintf("id: %s\nevent: %s\ndata: %s\n\n", id, event, body))
+}
+
+// writeKeepAlive sends a comment with the id of the latest update seen, including the ones filtered out, so
+// that a reconnecting client does not ask for updates it has no use for.
+func (st *sseStream) writeKeepAlive(id string) error {
+	return st.write(fmt.Sprintf(": keepalive\nid: %s\n\n", id))
+}
+
+// write sends text to the client. The server's write timeout would end the stream, so every write gets its own
+// deadline instead, when the connection is known (see connContext).
+func (st *sseStream) write(text string) error {
+	if st.conn != nil {
+		if err := st.conn.SetWriteDeadline(time.Now().Add(sseWriteWait)); err != nil {
+			return err
+		}
+	}
+	if _, err := io.WriteString(st.w, text); err != nil {
+		return err
+	}
+	st.flusher.Flush()
+	return nil
+}
+
+// eventID gives the SSE id of an update. IDs are prefixed with the service start time, so that an id from
+// before a restart is not mistaken for a recent one.
+func (s *Service) eventID(seq uint64) string {
+	return fmt.Sprintf("%d-%d", s.started.UnixNano(), seq)
+}
+
+// parseEventID returns the update number in an id given by eventID, and false if the id is not from this
+// run of the service.
+func (s *Service) parseEventID(id string) (uint64, bool) {
+	prefix := strconv.FormatInt(s.started.UnixNano(), 10) + "-"
+	if !strings.HasPrefix(id, prefix) {
+		return 0, false
+	}
+	seq, err := strconv.ParseUint(strings.TrimPrefix(id, prefix), 10, 64)
+	return seq, err == nil
+}
+
+// PricesStream streams price updates as Server-Sent Events, filtered by the same query parameters as
+// GET /prices. A new client first gets a snapshot event with all matching prices, then an update event
+// whenever one of them changes. A client which reconnects with a Last-Event-ID header gets the updates it
+// missed instead of a snapshot, as long as they are still kept.
+func (s *Service) PricesStream(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	filter, err := parsePriceFilter(r)
+	fields := filter.fields()
+	fields["lastEventID"] = r.Header.Get("Last-Event-ID")
+	log.WithContext(r.Context()).WithFields(fields).Debug("GET /prices/stream")
+	if err != nil {
+		writeError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		writeError(w, fmt.Errorf("streaming is not supported on this connection"), http.StatusInternalServerError)
+		return
+	}
+	st := &sseStream{w: w, flusher: flusher, conn: requestConn(r)}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	if err := st.write(""); err != nil {
+		return
+	}
+
+	// subscribe before catching up, so that no update is missed in between
+	updates, unsubscribe := s.pe.Subscribe(sseUpdateBuffer)
+	defer unsubscribe()
+
+	last, err := s.catchUp(st, filter, r.Header.Get("Last-Event-ID"))
+	if err != nil {
+		return
+	}
+
+	keepAlive := time.NewTicker(sseKeepAlivePeriod)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case update, ok := <-updates:
+			if !ok {
+				// the client can reconnect with its Last-Event-ID to get the updates it missed
+				log.WithContext(r.Context()).WithFields(log.Fields{
+					"remoteAddr": r.RemoteAddr,
+				}).Warn("Disconnecting stream client which is too slow")
+				return
+			}
+			if update.Seq <= last {
+				continue
+			}
+			if err := s.writeStreamUpdate(st, filter, update); err != nil {
+				return
+			}
+			last = update.Seq
+
+		case <-keepAlive.C:
+			if err := st.writeKeepAlive(s.eventID(last)); err != nil {
+				return
+			}
+
+		case <-s.closing:
+			return
+
+		case <-r.Context().Done():
+			// the client went away
+			return
+		}
+	}
+}
+
+// catchUp sends the updates missed since lastEventID if possible, else a snapshot. It returns the number of
+// the latest update the client has seen.
+func (s *Service) catchUp(st *sseStream, filter priceFilter, lastEventID string) (uint64, error) {
+	if seq, ok := s.parseEventID(lastEventID); ok {
+		if missed, ok := s.pe.UpdatesSince(seq); ok {
+			for _, update := range missed {
+				if err := s.writeStreamUpdate(st, filter, update); err != nil {
+					return 0, err
+				}
+				seq = update.Seq
+			}
+			return seq, nil
+		}
+	}
+
+	// updates published while the snapshot is taken may be in it and sent again after it, which is harmless
+	seq := s.pe.LastUpdateSeq()
+	snapshot := PricesResponse{
+		Prices: s.priceResponses(filter, s.pe.GetPrices(), time.Now()),
+	}
+	return seq, st.writeEvent(s.eventID(seq), StreamSnapshot, snapshot)
+}
+
+func (s *Service) writeStreamUpdate(st *sseStream, filter priceFilter, update pricing.PriceUpdate) error {
+	if !filter.matches(update.Price) {
+		return nil
+	}
+	freshness := pricing.GetFreshness(update.Info, s.pe.PriceMaxAge(update.Price), time.Now())
+	if !filter.matchesFreshness(freshness) {
+		return nil
+	}
+
+	return st.writeEvent(s.eventID(update.Seq), StreamUpdate, s.priceResponse(update.Price, update.Info, freshness))
+}
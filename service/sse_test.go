@@ -0,0 +1,156 @@
+package service
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"code.vegaprotocol.io/priceproxy/config"
+	"code.vegaprotocol.io/priceproxy/pricing"
+)
+
+// testWriteTimeout is the write timeout of the test server, which streams must outlive.
+const testWriteTimeout = 200 * time.Millisecond
+
+type sseEvent struct {
+	id, event, data string
+}
+
+// sseClient reads the events of one stream.
+type sseClient struct {
+	resp   *http.Response
+	reader *bufio.Reader
+}
+
+// startStreamServer serves a test service with a short write timeout, as getServer sets it up otherwise.
+func startStreamServer(t *testing.T, s *Service) *httptest.Server {
+	t.Helper()
+
+	server := httptest.NewUnstartedServer(s)
+	server.Config.WriteTimeout = testWriteTimeout
+	server.Config.ConnContext = connContext
+	server.Start()
+	t.Cleanup(server.Close)
+	return server
+}
+
+func openStream(t *testing.T, server *httptest.Server, query, lastEventID string) *sseClient {
+	t.Helper()
+
+	r, err := http.NewRequest(http.MethodGet, server.URL+"/prices/stream"+query, nil)
+	require.NoError(t, err)
+	if lastEventID != "" {
+		r.Header.Set("Last-Event-ID", lastEventID)
+	}
+	resp, err := server.Client().Do(r)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = resp.Body.Close() })
+	require.Equal(t, http.StatusOK, resp.StatusCode)
+	assert.Equal(t, "text/event-stream", resp.Header.Get("Content-Type"))
+	return &sseClient{resp: resp, reader: bufio.NewReader(resp.Body)}
+}
+
+// next returns the next event, skipping keepalive comments.
+func (c *sseClient) next(t *testing.T) sseEvent {
+	t.Helper()
+
+	var ev sseEvent
+	for {
+		line, err := c.reader.ReadString('\n')
+		require.NoError(t, err)
+		line = strings.TrimSuffix(line, "\n")
+		switch {
+		case line == "" && ev.event != "":
+			return ev
+		case strings.HasPrefix(line, "id: "):
+			ev.id = strings.TrimPrefix(line, "id: ")
+		case strings.HasPrefix(line, "event: "):
+			ev.event = strings.TrimPrefix(line, "event: ")
+		case strings.HasPrefix(line, "data: "):
+			ev.data = strings.TrimPrefix(line, "data: ")
+		}
+	}
+}
+
+func (c *sseClient) nextUpdate(t *testing.T) (string, PriceResponse) {
+	t.Helper()
+
+	ev := c.next(t)
+	require.Equal(t, StreamUpdate, ev.event)
+	var price PriceResponse
+	require.NoError(t, json.Unmarshal([]byte(ev.data), &price))
+	return ev.id, price
+}
+
+func TestPricesStream(t *testing.T) {
+	btc := config.PriceConfig{Source: "a", Base: "BTC", Quote: "USD", Factor: 1}
+	eth := config.PriceConfig{Source: "a", Base: "ETH", Quote: "USD", Factor: 1}
+	s := newTestService(t, config.PriceList{btc, eth})
+	update := func(pricecfg config.PriceConfig, price int64) {
+		s.pe.UpdatePrice(pricecfg, pricing.PriceInfo{Price: decimal.NewFromInt(price), LastUpdatedReal: time.Now()})
+	}
+	server := startStreamServer(t, s)
+
+	client := openStream(t, server, "?base=BTC", "")
+	ev := client.next(t)
+	assert.Equal(t, StreamSnapshot, ev.event)
+	assert.Equal(t, s.eventID(s.pe.LastUpdateSeq()), ev.id)
+	var snapshot PricesResponse
+	require.NoError(t, json.Unmarshal([]byte(ev.data), &snapshot))
+	require.Len(t, snapshot.Prices, 1)
+	assert.Equal(t, "BTC", snapshot.Prices[0].Base)
+	assert.Equal(t, "100", snapshot.Prices[0].Price.String())
+
+	// the stream outlives the write timeout of the server, and updates of prices left out by the filter are
+	// not sent
+	time.Sleep(2 * testWriteTimeout)
+	update(eth, 50)
+	update(btc, 101)
+	id, price := client.nextUpdate(t)
+	assert.Equal(t, s.eventID(s.pe.LastUpdateSeq()), id)
+	assert.Equal(t, "BTC", price.Base)
+	assert.Equal(t, "101", price.Price.String())
+
+	// a client reconnecting with the id of the last event it got gets the updates it missed
+	require.NoError(t, client.resp.Body.Close())
+	update(btc, 102)
+	update(eth, 51)
+	update(btc, 103)
+	client = openStream(t, server, "?base=BTC", id)
+	_, price = client.nextUpdate(t)
+	assert.Equal(t, "102", price.Price.String())
+	_, price = client.nextUpdate(t)
+	assert.Equal(t, "103", price.Price.String())
+
+	// an id from before a restart gets a snapshot
+	require.NoError(t, client.resp.Body.Close())
+	client = openStream(t, server, "?base=ETH", "1-1")
+	ev = client.next(t)
+	assert.Equal(t, StreamSnapshot, ev.event)
+	require.NoError(t, json.Unmarshal([]byte(ev.data), &snapshot))
+	require.Len(t, snapshot.Prices, 1)
+	assert.Equal(t, "51", snapshot.Prices[0].Price.String())
+
+	// streams are counted in the HTTP metrics when they end
+	require.NoError(t, client.resp.Body.Close())
+	requests := s.httpMetrics.requests.WithLabelValues("PricesStream", http.MethodGet, "200")
+	assert.Eventually(t, func() bool {
+		return testutil.ToFloat64(requests) == 3
+	}, 5*time.Second, 10*time.Millisecond)
+}
+
+func TestPricesStreamInvalidFilter(t *testing.T) {
+	s := newTestService(t, config.PriceList{{Source: "a", Base: "BTC", Quote: "USD", Factor: 1}})
+
+	w := serve(s, http.MethodGet, "/prices/stream?stale=maybe", "", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,89 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"code.vegaprotocol.io/priceproxy/config"
+	"code.vegaprotocol.io/priceproxy/pricing"
+)
+
+var (
+	testTracerProvider     *sdktrace.TracerProvider
+	testTracerProviderOnce sync.Once
+)
+
+// recordSpans records the spans ended until the end of the test. The global tracer provider is only set once,
+// as the tracer of the package keeps using the first one.
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+
+	testTracerProviderOnce.Do(func() {
+		testTracerProvider = sdktrace.NewTracerProvider()
+		otel.SetTracerProvider(testTracerProvider)
+		otel.SetTextMapPropagator(propagation.TraceContext{})
+	})
+	recorder := tracetest.NewSpanRecorder()
+	testTracerProvider.RegisterSpanProcessor(recorder)
+	t.Cleanup(func() { testTracerProvider.UnregisterSpanProcessor(recorder) })
+	return recorder
+}
+
+// serveTraced sends a request continuing the trace of a caller, and returns the span of the request.
+func serveTraced(
+	t *testing.T,
+	s *Service,
+	recorder *tracetest.SpanRecorder,
+	traceID trace.TraceID,
+	target string,
+) sdktrace.ReadOnlySpan {
+	t.Helper()
+
+	r := httptest.NewRequest(http.MethodGet, target, nil)
+	r.Header.Set("traceparent", "00-"+traceID.String()+"-0102030405060708-01")
+	s.ServeHTTP(httptest.NewRecorder(), r)
+
+	for _, span := range recorder.Ended() {
+		if span.SpanContext().TraceID() == traceID && span.SpanKind() == trace.SpanKindServer {
+			return span
+		}
+	}
+	require.FailNow(t, "no span for the request", target)
+	return nil
+}
+
+func TestTraced(t *testing.T) {
+	btc := config.PriceConfig{Source: "a", Base: "BTC", Quote: "USD", Factor: 1}
+	s := newTestService(t, config.PriceList{btc})
+	recorder := recordSpans(t)
+
+	span := serveTraced(t, s, recorder, trace.TraceID{1}, "/prices?base=BTC")
+	assert.Equal(t, "PricesGet", span.Name())
+	assert.Equal(t, trace.SpanID{1, 2, 3, 4, 5, 6, 7, 8}, span.Parent().SpanID())
+	assert.True(t, span.Parent().IsRemote())
+	assert.Contains(t, span.Attributes(), semconv.HTTPMethod(http.MethodGet))
+	assert.Contains(t, span.Attributes(), semconv.HTTPTarget("/prices?base=BTC"))
+	assert.Contains(t, span.Attributes(), semconv.HTTPStatusCode(http.StatusOK))
+	assert.Equal(t, codes.Unset, span.Status().Code)
+
+	// server errors end the span with an error status
+	s.pe.UpdatePrice(btc, pricing.PriceInfo{Price: decimal.NewFromInt(100), LastUpdatedReal: time.Now().Add(-time.Hour)})
+	span = serveTraced(t, s, recorder, trace.TraceID{2}, "/readyz")
+	assert.Equal(t, "ReadyzGet", span.Name())
+	assert.Contains(t, span.Attributes(), semconv.HTTPStatusCode(http.StatusServiceUnavailable))
+	assert.Equal(t, codes.Error, span.Status().Code)
+}
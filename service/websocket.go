@@ -0,0 +1,229 @@
+package service
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/julienschmidt/httprouter"
+	log "github.com/sirupsen/logrus"
+
+	"code.vegaprotocol.io/priceproxy/pricing"
+)
+
+const (
+	// wsWriteWait is how long a client gets to accept one message.
+	wsWriteWait = 10 * time.Second
+	// wsPongWait is how long a client may go without sending anything, pongs included.
+	wsPongWait = 60 * time.Second
+	// wsPingPeriod is how often clients are pinged. It must be less than wsPongWait.
+	wsPingPeriod = 30 * time.Second
+	// wsMaxRequestSize is the largest message accepted from a client.
+	wsMaxRequestSize = 4096
+	// wsUpdateBuffer is how many updates may be waiting for a client before it is disconnected as too slow.
+	wsUpdateBuffer = 1024
+)
+
+// Message types sent on /ws/prices.
+const (
+	WebSocketSnapshot = "snapshot"
+	WebSocketUpdate   = "update"
+	WebSocketError    = "error"
+)
+
+// WebSocketRequest is sent by clients of /ws/prices to replace their filter. The fields have the same meaning
+// as the query parameters of GET /prices.
+type WebSocketRequest struct {
+	Type   string `json:"type"`
+	Source string `json:"source"`
+	Base   string `json:"base"`
+	Quote  string `json:"quote"`
+	Wander *bool  `json:"wander"`
+	Stale  *bool  `json:"stale"`
+}
+
+// WebSocketMessage is sent to clients of /ws/prices: all matching prices in a snapshot, one price in an
+// update, or an error.
+type WebSocketMessage struct {
+	Type   string           `json:"type"`
+	Prices []*PriceResponse `json:"prices,omitempty"`
+	Price  *PriceResponse   `json:"price,omitempty"`
+	Error  string           `json:"error,omitempty"`
+}
+
+// wsSubscription is a filter requested by a client, or the error found in its request.
+type wsSubscription struct {
+	filter priceFilter
+	err    error
+}
+
+var upgrader = websocket.Upgrader{
+	// prices are public, and GET /prices can be read from any origin too
+	CheckOrigin: func(r *http.Request) bool { return true },
+}
+
+// PricesWebSocket streams prices over a WebSocket: a snapshot of the prices matching the filter given as
+// query parameters (as for GET /prices), then an update whenever one of them changes. Clients may send a
+// WebSocketRequest at any time to replace their filter, which is followed by a new snapshot, or by an error
+// if the request is invalid.
+func (s *Service) PricesWebSocket(w http.ResponseWriter, r *http.Request, ps httprouter.Params) {
+	filter, err := parsePriceFilter(r)
+	log.WithContext(r.Context()).WithFields(filter.fields()).Debug("GET /ws/prices")
+	if err != nil {
+		writeError(w, err, http.StatusBadRequest)
+		return
+	}
+
+	conn, err := upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		// the upgrader has already replied to the client
+		log.WithContext(r.Context()).WithFields(log.Fields{
+			"error":      err.Error(),
+			"remoteAddr": r.RemoteAddr,
+		}).Debug("Failed to upgrade to WebSocket")
+		return
+	}
+	defer conn.Close()
+
+	// subscribe before taking the snapshot, so that no update is missed in between
+	updates, unsubscribe := s.pe.Subscribe(wsUpdateBuffer)
+	defer unsubscribe()
+
+	// the reader stops when the writer returns, and the writer when the reader fails
+	subscriptions := make(chan wsSubscription)
+	writerDone := make(chan struct{})
+	defer close(writerDone)
+	readerDone := make(chan struct{})
+	go s.readWebSocket(conn, subscriptions, writerDone, readerDone)
+
+	ping := time.NewTicker(wsPingPeriod)
+	defer ping.Stop()
+
+	if err := s.writeWebSocketSnapshot(conn, filter); err != nil {
+		return
+	}
+
+	for {
+		select {
+		case sub := <-subscriptions:
+			if sub.err != nil {
+				err = writeWebSocket(conn, WebSocketMessage{Type: WebSocketError, Error: sub.err.Error()})
+			} else {
+				filter = sub.filter
+				err = s.writeWebSocketSnapshot(conn, filter)
+			}
+			if err != nil {
+				return
+			}
+
+		case update, ok := <-updates:
+			if !ok {
+				log.WithContext(r.Context()).WithFields(log.Fields{
+					"remoteAddr": r.RemoteAddr,
+				}).Warn("Disconnecting WebSocket client which is too slow")
+				closeWebSocket(conn, websocket.CloseTryAgainLater, "too slow")
+				return
+			}
+			if err := s.writeWebSocketUpdate(conn, filter, update); err != nil {
+				return
+			}
+
+		case <-ping.C:
+			if err := conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(wsWriteWait)); err != nil {
+				return
+			}
+
+		case <-s.closing:
+			closeWebSocket(conn, websocket.CloseGoingAway, "shutting down")
+			return
+
+		case <-readerDone:
+			return
+		}
+	}
+}
+
+// readWebSocket reads the requests of a client until the connection fails or writerDone is closed, and passes
+// them on to the writer. done is closed when it returns.
+func (s *Service) readWebSocket(
+	conn *websocket.Conn,
+	subscriptions chan<- wsSubscription,
+	writerDone <-chan struct{},
+	done chan<- struct{},
+) {
+	defer close(done)
+
+	conn.SetReadLimit(wsMaxRequestSize)
+	_ = conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	})
+
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			return
+		}
+		_ = conn.SetReadDeadline(time.Now().Add(wsPongWait))
+
+		var sub wsSubscription
+		var req WebSocketRequest
+		if err := json.Unmarshal(data, &req); err != nil {
+			sub.err = fmt.Errorf("failed to parse request: %w", err)
+		} else if req.Type != "subscribe" {
+			sub.err = fmt.Errorf("unknown request type: %q", req.Type)
+		} else {
+			sub.filter = priceFilter{
+				source: req.Source,
+				base:   req.Base,
+				quote:  req.Quote,
+				wander: req.Wander,
+				stale:  req.Stale,
+			}
+		}
+
+		select {
+		case subscriptions <- sub:
+		case <-writerDone:
+			return
+		}
+	}
+}
+
+func (s *Service) writeWebSocketSnapshot(conn *websocket.Conn, filter priceFilter) error {
+	return writeWebSocket(conn, WebSocketMessage{
+		Type:   WebSocketSnapshot,
+		Prices: s.priceResponses(filter, s.pe.GetPrices(), time.Now()),
+	})
+}
+
+func (s *Service) writeWebSocketUpdate(conn *websocket.Conn, filter priceFilter, update pricing.PriceUpdate) error {
+	if !filter.matches(update.Price) {
+		return nil
+	}
+	freshness := pricing.GetFreshness(update.Info, s.pe.PriceMaxAge(update.Price), time.Now())
+	if !filter.matchesFreshness(freshness) {
+		return nil
+	}
+
+	return writeWebSocket(conn, WebSocketMessage{
+		Type:  WebSocketUpdate,
+		Price: s.priceResponse(update.Price, update.Info, freshness),
+	})
+}
+
+func writeWebSocket(conn *websocket.Conn, msg WebSocketMessage) error {
+	if err := conn.SetWriteDeadline(time.Now().Add(wsWriteWait)); err != nil {
+		return err
+	}
+	if err := conn.WriteJSON(msg); err != nil {
+		return fmt.Errorf("failed to write to WebSocket: %w", err)
+	}
+	return nil
+}
+
+func closeWebSocket(conn *websocket.Conn, code int, text string) {
+	_ = conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(code, text), time.Now().Add(wsWriteWait))
+}
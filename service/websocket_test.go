@@ -0,0 +1,140 @@
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"code.vegaprotocol.io/priceproxy/config"
+	"code.vegaprotocol.io/priceproxy/pricing"
+)
+
+// dialWebSocket connects to /ws/prices of a test service, with the given query string.
+func dialWebSocket(t *testing.T, s *Service, query string) *websocket.Conn {
+	t.Helper()
+
+	server := httptest.NewServer(s)
+	t.Cleanup(server.Close)
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/ws/prices"+query, nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = conn.Close() })
+	return conn
+}
+
+func readWebSocketMessage(t *testing.T, conn *websocket.Conn) WebSocketMessage {
+	t.Helper()
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	var msg WebSocketMessage
+	require.NoError(t, conn.ReadJSON(&msg))
+	return msg
+}
+
+func TestPricesWebSocket(t *testing.T) {
+	btc := config.PriceConfig{Source: "a", Base: "BTC", Quote: "USD", Factor: 1}
+	eth := config.PriceConfig{Source: "a", Base: "ETH", Quote: "USD", Factor: 1}
+	s := newTestService(t, config.PriceList{btc, eth})
+	update := func(pricecfg config.PriceConfig, price int64) {
+		s.pe.UpdatePrice(pricecfg, pricing.PriceInfo{Price: decimal.NewFromInt(price), LastUpdatedReal: time.Now()})
+	}
+
+	conn := dialWebSocket(t, s, "?base=BTC")
+	msg := readWebSocketMessage(t, conn)
+	assert.Equal(t, WebSocketSnapshot, msg.Type)
+	require.Len(t, msg.Prices, 1)
+	assert.Equal(t, "BTC", msg.Prices[0].Base)
+	assert.Equal(t, "100", msg.Prices[0].Price.String())
+
+	// updates of prices left out by the filter are not sent
+	update(eth, 50)
+	update(btc, 101)
+	msg = readWebSocketMessage(t, conn)
+	assert.Equal(t, WebSocketUpdate, msg.Type)
+	require.NotNil(t, msg.Price)
+	assert.Equal(t, "BTC", msg.Price.Base)
+	assert.Equal(t, "101", msg.Price.Price.String())
+
+	// subscribing again replaces the filter: BTC is unsubscribed
+	require.NoError(t, conn.WriteJSON(WebSocketRequest{Type: "subscribe", Base: "ETH"}))
+	msg = readWebSocketMessage(t, conn)
+	assert.Equal(t, WebSocketSnapshot, msg.Type)
+	require.Len(t, msg.Prices, 1)
+	assert.Equal(t, "ETH", msg.Prices[0].Base)
+	assert.Equal(t, "50", msg.Prices[0].Price.String())
+
+	update(btc, 102)
+	update(eth, 51)
+	msg = readWebSocketMessage(t, conn)
+	assert.Equal(t, WebSocketUpdate, msg.Type)
+	require.NotNil(t, msg.Price)
+	assert.Equal(t, "ETH", msg.Price.Base)
+	assert.Equal(t, "51", msg.Price.Price.String())
+
+	// invalid requests get an error, and leave the filter as it was
+	require.NoError(t, conn.WriteJSON(WebSocketRequest{Type: "unsubscribe"}))
+	msg = readWebSocketMessage(t, conn)
+	assert.Equal(t, WebSocketError, msg.Type)
+	assert.Contains(t, msg.Error, "unknown request type")
+
+	require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte("{")))
+	msg = readWebSocketMessage(t, conn)
+	assert.Equal(t, WebSocketError, msg.Type)
+	assert.Contains(t, msg.Error, "failed to parse request")
+
+	update(eth, 52)
+	msg = readWebSocketMessage(t, conn)
+	assert.Equal(t, WebSocketUpdate, msg.Type)
+	require.NotNil(t, msg.Price)
+	assert.Equal(t, "52", msg.Price.Price.String())
+}
+
+func TestPricesWebSocketInvalidFilter(t *testing.T) {
+	s := newTestService(t, config.PriceList{{Source: "a", Base: "BTC", Quote: "USD", Factor: 1}})
+
+	w := serve(s, http.MethodGet, "/ws/prices?stale=maybe", "", "")
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
+
+func TestReadWebSocketStopsWithWriter(t *testing.T) {
+	s := &Service{closing: make(chan struct{})}
+	subscriptions := make(chan wsSubscription)
+	writerDone := make(chan struct{})
+	readerDone := make(chan struct{})
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		conn, err := upgrader.Upgrade(w, r, nil)
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+		s.readWebSocket(conn, subscriptions, writerDone, readerDone)
+	}))
+	defer server.Close()
+
+	conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(server.URL, "http"), nil)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	// the request is read, but the writer has gone and never takes it
+	require.NoError(t, conn.WriteJSON(WebSocketRequest{Type: "subscribe", Base: "BTC"}))
+	select {
+	case <-readerDone:
+		t.Fatal("reader returned while the connection is open")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(writerDone)
+	select {
+	case <-readerDone:
+	case <-time.After(5 * time.Second):
+		t.Fatal("reader blocked after the writer returned")
+	}
+}
@@ -0,0 +1,33 @@
+package tracing
+
+import (
+	"context"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/sirupsen/logrus/hooks/test"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+func TestLogHook(t *testing.T) {
+	logger, entries := test.NewNullLogger()
+	logger.AddHook(LogHook{})
+
+	ctx, span := sdktrace.NewTracerProvider().Tracer("test").Start(context.Background(), "span")
+	defer span.End()
+
+	logger.WithContext(ctx).WithFields(log.Fields{"source": "a"}).Info("traced")
+	logger.WithContext(context.Background()).Info("not traced")
+	logger.Info("no context")
+
+	require.Len(t, entries.AllEntries(), 3)
+	traced := entries.AllEntries()[0]
+	assert.Equal(t, span.SpanContext().TraceID().String(), traced.Data["trace_id"])
+	assert.Equal(t, span.SpanContext().SpanID().String(), traced.Data["span_id"])
+	assert.Equal(t, "a", traced.Data["source"])
+	for _, entry := range entries.AllEntries()[1:] {
+		assert.NotContains(t, entry.Data, "trace_id")
+	}
+}